@@ -17,12 +17,15 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/earthboundkid/resperr/v2"
 	"github.com/getsentry/sentry-go"
+	"github.com/go-chi/chi/v5/middleware"
 
 	"github.com/spotlightpa/almanack/internal/almlog"
 	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
 	"github.com/spotlightpa/almanack/internal/layouts"
 	"github.com/spotlightpa/almanack/internal/services/netlifyid"
 	"github.com/spotlightpa/almanack/internal/utils/stringx"
@@ -52,6 +55,22 @@ func (app *appEnv) replyErr(w http.ResponseWriter, r *http.Request, err error) {
 	})
 }
 
+// replyConflict tells the client that its copy of a resource is stale
+// and sends back the current server copy so that it can merge its changes.
+func (app *appEnv) replyConflict(w http.ResponseWriter, r *http.Request, current any, format string, v ...any) {
+	err := resperr.New(http.StatusConflict, format, v...)
+	app.logErr(r.Context(), err)
+	app.replyJSON(http.StatusConflict, w, struct {
+		Status  int        `json:"status"`
+		Details url.Values `json:"details"`
+		Current any        `json:"current"`
+	}{
+		http.StatusConflict,
+		url.Values{"": []string{resperr.UserMessage(err)}},
+		current,
+	})
+}
+
 func (app *appEnv) replyNewErr(code int, w http.ResponseWriter, r *http.Request, format string, v ...any) {
 	app.replyErr(w, r, resperr.New(code, format, v...))
 }
@@ -177,6 +196,49 @@ func (app *appEnv) hasRoleMiddleware(role string) func(next http.Handler) http.H
 	}
 }
 
+// auditMiddleware records every request that changes something in the audit log.
+func (app *appEnv) auditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			app.replyErr(w, r, resperr.New(http.StatusBadRequest, "could not read request: %w", err))
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		ctx, rec := almsvc.NewAuditContext(r.Context())
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		target := cmp.Or(
+			rec.Target,
+			almsvc.AuditTargetFromJSON(body),
+			r.URL.Query().Get("id"),
+			r.URL.Query().Get("location"),
+		)
+		after := rec.After
+		if after == nil {
+			after = body
+		}
+		// Record the event even if the request itself timed out
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+		defer cancel()
+		if err := app.svc.Queries.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+			EmailAddress: netlifyid.FromContext(r.Context()).Email(),
+			Route:        r.Pattern,
+			Target:       target,
+			StatusCode:   int32(cmp.Or(ww.Status(), http.StatusOK)),
+			Before:       almsvc.SummarizeForAudit(rec.Before),
+			After:        almsvc.SummarizeForAudit(after),
+		}); err != nil {
+			app.logErr(ctx, err)
+		}
+	})
+}
+
 func (app *appEnv) maxSizeMiddleware(next http.Handler) http.Handler {
 	const (
 		megabyte = 1 << 20
@@ -233,6 +295,18 @@ func boolFromQuery(r *http.Request, param string) (val bool, err error) {
 	return
 }
 
+func timeFromQuery(r *http.Request, param string) (val time.Time, err error) {
+	s := r.URL.Query().Get(param)
+	if s == "" {
+		return
+	}
+	val, err = time.Parse(time.RFC3339, s)
+	if err != nil {
+		err = resperr.E{E: err, M: fmt.Sprintf("Could not interpret %s=%q", param, s)}
+	}
+	return
+}
+
 func (app *appEnv) replyHTML(w http.ResponseWriter, r *http.Request, t *template.Template, data any) {
 	var buf bytes.Buffer
 	if err := t.Execute(&buf, data); err != nil {
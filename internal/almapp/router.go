@@ -29,6 +29,7 @@ func (app *appEnv) routes() http.Handler {
 	// Start public endpoints
 	standardMW.
 		HandleFunc(mux, `GET /api/bookmarklet/{slug}`, app.getBookmarklet).
+		HandleFunc(mux, `GET /api/calendar.ics`, app.getCalendarICS).
 		HandleFunc(mux, `GET /api/healthcheck`, app.ping).
 		HandleFunc(mux, `GET /api/healthcheck/{code}`, app.pingErr).
 		HandleFunc(mux, `POST /api/identity-hook`, app.postIdentityHook)
@@ -38,7 +39,10 @@ func (app *appEnv) routes() http.Handler {
 
 	authMW.Control(mux, `GET /api/user-info`, app.userInfo)
 
-	partnerMW := authMW.With(app.hasRoleMiddleware("editor"))
+	partnerMW := authMW.With(
+		app.hasRoleMiddleware("editor"),
+		app.auditMiddleware,
+	)
 
 	// Start partner endpoints
 	partnerMW.
@@ -46,16 +50,26 @@ func (app *appEnv) routes() http.Handler {
 		Control(mux, `GET /api/shared-articles`, app.listSharedArticles)
 	// End partner endpoints
 
-	spotlightMW := authMW.With(app.hasRoleMiddleware("Spotlight PA"))
+	spotlightMW := authMW.With(
+		app.hasRoleMiddleware("Spotlight PA"),
+		app.auditMiddleware,
+	)
 
 	// Start Spotlight endpoints
 	spotlightMW.
 		HandleFunc(mux, `GET /api/all-series`, app.listAllSeries).
 		HandleFunc(mux, `GET /api/all-topics`, app.listAllTopics).
+		Control(mux, `POST /api/archive-export`, app.postArchiveExport).
+		Control(mux, `POST /api/archive-import`, app.postArchiveImport).
+		Control(mux, `POST /api/archive-upload`, app.postArchiveUpload).
+		Control(mux, `GET /api/audit-log`, app.listAuditLog).
+		Control(mux, `POST /api/author`, app.postAuthor).
 		HandleFunc(mux, `GET /api/authorized-addresses`, app.listAddresses).
 		HandleFunc(mux, `POST /api/authorized-addresses`, app.postAddress).
 		HandleFunc(mux, `GET /api/authorized-domains`, app.listDomains).
 		HandleFunc(mux, `POST /api/authorized-domains`, app.postDomain).
+		Control(mux, `GET /api/authors`, app.listAuthors).
+		Control(mux, `GET /api/calendar`, app.getCalendar).
 		HandleFunc(mux, `POST /api/create-signed-upload`, app.postSignedUpload).
 		Control(mux, `POST /api/donor-wall`, app.postDonorWall).
 		HandleFunc(mux, `POST /api/files-create`, app.postFileCreate).
@@ -65,14 +79,26 @@ func (app *appEnv) routes() http.Handler {
 		HandleFunc(mux, `POST /api/gdocs-doc`, app.postGDocsDoc).
 		HandleFunc(mux, `POST /api/image-update`, app.postImageUpdate).
 		HandleFunc(mux, `GET /api/images`, app.listImages).
+		Control(mux, `GET /api/job`, app.getJob).
+		Control(mux, `GET /api/link-check-report`, app.getLinkCheckReport).
 		HandleFunc(mux, `POST /api/message`, app.postMessage).
 		HandleFunc(mux, `GET /api/page`, app.getPage).
 		HandleFunc(mux, `POST /api/page`, app.postPage).
+		Control(mux, `POST /api/page-bulk-edit`, app.postPageBulkEdit).
 		HandleFunc(mux, `POST /api/page-json`, app.postPageJSON).
+		Control(mux, `POST /api/page-correction`, app.postPageCorrection).
+		Control(mux, `GET /api/page-corrections`, app.listPageCorrections).
 		HandleFunc(mux, `POST /api/page-create`, app.postPageCreate).
 		Control(mux, `POST /api/page-load`, app.postPageLoad).
+		Control(mux, `POST /api/page-lock`, app.postPageLock).
+		Control(mux, `GET /api/page-related`, app.getPageRelated).
+		Control(mux, `POST /api/page-restore`, app.postPageRestore).
+		Control(mux, `GET /api/page-revision-diff`, app.getPageRevisionDiff).
+		Control(mux, `GET /api/page-revisions`, app.listPageRevisions).
+		Control(mux, `POST /api/page-unpublish`, app.postPageUnpublish).
 		HandleFunc(mux, `GET /api/pages`, app.listPages).
 		HandleFunc(mux, `GET /api/pages-by-fts`, app.listPagesByFTS).
+		Control(mux, `GET /api/pages-search`, app.listPagesSearch).
 		HandleFunc(mux, `POST /api/page-refresh`, app.postPageRefresh).
 		HandleFunc(mux, `POST /api/shared-article`, app.postSharedArticle).
 		HandleFunc(mux, `POST /api/shared-article-from-gdocs`, app.postSharedArticleFromGDocs).
@@ -81,7 +107,9 @@ func (app *appEnv) routes() http.Handler {
 		Control(mux, `GET /api/site-data`, app.getSiteData).
 		Control(mux, `POST /api/site-data`, app.postSiteData).
 		HandleFunc(mux, `GET /api/site-params`, app.siteDataGet(almsvc.SiteParamsLoc)).
-		HandleFunc(mux, `POST /api/site-params`, app.siteDataSet(almsvc.SiteParamsLoc))
+		HandleFunc(mux, `POST /api/site-params`, app.siteDataSet(almsvc.SiteParamsLoc)).
+		Control(mux, `POST /api/taxonomy-merge`, app.postTaxonomyMerge).
+		Control(mux, `POST /api/taxonomy-rename`, app.postTaxonomyRename)
 	// End spotlight endpoints
 
 	// Don't trust this middleware!
@@ -106,6 +134,8 @@ func (app *appEnv) routes() http.Handler {
 	backgroundMW.
 		Control(mux, `GET /api-background/cron`, app.backgroundCron).
 		Control(mux, `GET /api-background/images`, app.backgroundImages).
+		Control(mux, `GET /api-background/jobs`, app.backgroundJobs).
+		Control(mux, `GET /api-background/link-check`, app.backgroundLinkCheck).
 		Control(mux, `GET /api-background/refresh-pages`, app.backgroundRefreshPages).
 		Control(mux, `GET /api-background/sleep/{duration}`, app.backgroundSleep)
 	backgroundMW.
@@ -61,6 +61,12 @@ func (app *appEnv) backgroundCron(w http.ResponseWriter, r *http.Request) http.H
 				app.logErr(r.Context(), warning)
 			}
 			errs = append(errs, poperr)
+			// Take down expired pages before pushing new site config
+			poperr, warning = app.svc.PopExpiredPages(r.Context())
+			if warning != nil {
+				app.logErr(r.Context(), warning)
+			}
+			errs = append(errs, poperr)
 			keys, err := app.svc.Queries.ListSiteKeys(r.Context())
 			if err != nil {
 				return err
@@ -74,15 +80,38 @@ func (app *appEnv) backgroundCron(w http.ResponseWriter, r *http.Request) http.H
 			// Wrapping each return so single errors don't get unwrapped
 			return errors.Join(app.svc.UpdateMostPopular(r.Context()))
 		},
+		func() error {
+			return errors.Join(app.svc.UpdateSitemaps(r.Context()))
+		},
+		func() error {
+			return errors.Join(app.svc.UpdateCorrectionsFeed(r.Context()))
+		},
 		func() error {
 			return errors.Join(app.svc.UploadPendingImages(r.Context()))
 		},
 		func() error {
-			return errors.Join(app.svc.ProcessGDocs(r.Context()))
+			// Watch first so edited documents are processed in the same run
+			return errors.Join(
+				app.svc.WatchGDocs(r.Context()),
+				app.svc.ProcessGDocs(r.Context()),
+			)
 		},
 		func() error {
 			return errors.Join(app.svc.Queries.DeleteGDocsDocWhereUnunused(r.Context()))
 		},
+		func() error {
+			return errors.Join(app.svc.CleanPageLocks(r.Context()))
+		},
+		func() error {
+			// Queue any nightly jobs before running pending ones
+			return errors.Join(
+				app.svc.QueueRelatedPagesJob(r.Context()),
+				app.svc.RunPendingJobs(r.Context()),
+			)
+		},
+		func() error {
+			return errors.Join(app.svc.CheckLinks(r.Context(), 50))
+		},
 		func() error {
 			return errors.Join(updateMD5s(
 				r.Context(),
@@ -194,6 +223,16 @@ func (app *appEnv) backgroundImages(w http.ResponseWriter, r *http.Request) http
 	return app.jsonAccepted("OK")
 }
 
+func (app *appEnv) backgroundJobs(w http.ResponseWriter, r *http.Request) http.Handler {
+	app.logStart(r)
+
+	if err := app.svc.RunPendingJobs(r.Context()); err != nil {
+		return app.jsonErr(err)
+	}
+
+	return app.jsonAccepted("OK")
+}
+
 func updateMD5s[T any](
 	ctx context.Context,
 	list func(context.Context, int32) ([]T, error),
@@ -228,3 +267,16 @@ func (app *appEnv) postMigrate(w http.ResponseWriter, r *http.Request) http.Hand
 
 	return app.jsonOK(http.StatusText(http.StatusOK))
 }
+
+func (app *appEnv) backgroundLinkCheck(w http.ResponseWriter, r *http.Request) http.Handler {
+	app.logStart(r)
+
+	if err := app.svc.SyncPublishedPageLinks(r.Context()); err != nil {
+		return app.jsonErr(err)
+	}
+	if err := app.svc.CheckLinks(r.Context(), 500); err != nil {
+		return app.jsonErr(err)
+	}
+
+	return app.jsonAccepted("OK")
+}
@@ -1,6 +1,8 @@
 package almapp
 
 import (
+	"bytes"
+	"crypto/subtle"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
@@ -11,6 +13,7 @@ import (
 	"github.com/earthboundkid/resperr/v2"
 	"github.com/earthboundkid/slackhook/v2"
 	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
 	"github.com/spotlightpa/almanack/internal/db"
 	"github.com/spotlightpa/almanack/internal/services/jwthook"
 	"github.com/spotlightpa/almanack/internal/services/netlifyid"
@@ -61,6 +64,32 @@ func (app *appEnv) getBookmarklet(w http.ResponseWriter, r *http.Request) {
 		http.StatusTemporaryRedirect)
 }
 
+func (app *appEnv) getCalendarICS(w http.ResponseWriter, r *http.Request) {
+	app.logStart(r)
+
+	token := r.URL.Query().Get("token")
+	if app.svc.CalendarToken == "" ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(app.svc.CalendarToken)) != 1 {
+		app.replyNewErr(http.StatusForbidden, w, r, "bad calendar token")
+		return
+	}
+	now := time.Now()
+	events, err := app.svc.Calendar(r.Context(),
+		now.Add(-almsvc.CalendarFeedPast), now.Add(almsvc.CalendarFeedFuture))
+	if err != nil {
+		app.replyErr(w, r, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err = almsvc.WriteCalendarICS(&buf, events, almsvc.DeployURL, now); err != nil {
+		app.replyErr(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	buf.WriteTo(w)
+}
+
 func (app *appEnv) postIdentityHook(w http.ResponseWriter, r *http.Request) {
 	app.logStart(r)
 
@@ -9,14 +9,15 @@ import (
 	"maps"
 	"net/http"
 	"net/url"
-	"path"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/carlmjohnson/flowmatic"
 	"github.com/earthboundkid/emailx/v2"
 	"github.com/earthboundkid/resperr/v2"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/spotlightpa/almanack/internal/almlog"
 	"github.com/spotlightpa/almanack/internal/almsvc"
 	"github.com/spotlightpa/almanack/internal/db"
@@ -25,6 +26,7 @@ import (
 	"github.com/spotlightpa/almanack/internal/utils/paginate"
 	"github.com/spotlightpa/almanack/internal/utils/slicex"
 	"github.com/spotlightpa/almanack/internal/utils/stringx"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
 )
 
 func (app *appEnv) postMessage(w http.ResponseWriter, r *http.Request) {
@@ -111,6 +113,9 @@ func (app *appEnv) postImageUpdate(w http.ResponseWriter, r *http.Request) {
 	if !app.readJSON(w, r, &userData) {
 		return
 	}
+	if before, err := app.svc.Queries.GetImageByPath(r.Context(), userData.Path); err == nil {
+		almsvc.SetAuditTarget(r.Context(), userData.Path, before)
+	}
 	var (
 		res db.Image
 		err error
@@ -119,6 +124,7 @@ func (app *appEnv) postImageUpdate(w http.ResponseWriter, r *http.Request) {
 		app.replyErr(w, r, err)
 		return
 	}
+	almsvc.SetAuditAfter(r.Context(), res)
 	app.replyJSON(http.StatusOK, w, &res)
 }
 
@@ -162,21 +168,26 @@ func (app *appEnv) postDomain(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, _ := app.svc.Queries.GetRolesForDomain(r.Context(), req.Domain)
+	almsvc.SetAuditTarget(r.Context(), req.Domain, before)
+
 	var roles []string
 	if !req.Remove {
 		roles = []string{"editor"}
 	}
 
-	if _, err := app.svc.Queries.UpsertRolesForDomain(
+	saved, err := app.svc.Queries.UpsertRolesForDomain(
 		r.Context(),
 		db.UpsertRolesForDomainParams{
 			Domain: req.Domain,
 			Roles:  roles,
 		},
-	); err != nil {
+	)
+	if err != nil {
 		app.replyErr(w, r, err)
 		return
 	}
+	almsvc.SetAuditAfter(r.Context(), saved.Roles)
 
 	domains, err := app.svc.Queries.ListDomainsWithRole(r.Context(), "editor")
 	if err != nil {
@@ -225,26 +236,28 @@ func (app *appEnv) postAddress(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, _ := app.svc.Queries.GetRolesForAddress(r.Context(), req.Address)
+	almsvc.SetAuditTarget(r.Context(), req.Address, before)
+
 	var roles []string
 	if !req.Remove {
 		roles = []string{"editor"}
 	}
 
-	if _, err := app.svc.Queries.UpsertRolesForAddress(
+	saved, err := app.svc.Queries.UpsertRolesForAddress(
 		r.Context(),
 		db.UpsertRolesForAddressParams{
 			EmailAddress: req.Address,
 			Roles:        roles,
 		},
-	); err != nil {
+	)
+	if err != nil {
 		app.replyErr(w, r, err)
 		return
 	}
+	almsvc.SetAuditAfter(r.Context(), saved.Roles)
 
-	var (
-		resp response
-		err  error
-	)
+	var resp response
 	resp.Addresses, err = app.svc.Queries.ListAddressesWithRole(r.Context(), "editor")
 	if err != nil {
 		app.replyErr(w, r, err)
@@ -551,16 +564,41 @@ func (app *appEnv) getPage(w http.ResponseWriter, r *http.Request) {
 		page.Body = ""
 		delete(page.Frontmatter, "raw-content")
 	}
-	app.replyJSON(http.StatusOK, w, page)
+	// The page editor asks for a lock to let others know it is open
+	var editors []db.PageLock
+	if lock, _ := boolFromQuery(r, "lock"); lock {
+		editors, err = app.svc.HeartbeatPageLock(r.Context(), page.ID)
+	} else {
+		editors, err = app.svc.ListPageEditors(r.Context(), page.ID)
+	}
+	if err != nil {
+		app.replyErr(w, r, err)
+		return
+	}
+	sourceChangedAt, err := app.svc.PageSourceChangedAt(r.Context(), page.ID)
+	if err != nil {
+		app.replyErr(w, r, err)
+		return
+	}
+	app.replyJSON(http.StatusOK, w, struct {
+		db.Page
+		Editors         []db.PageLock `json:"editors"`
+		SourceChangedAt *time.Time    `json:"source_changed_at"`
+	}{page, editors, sourceChangedAt})
 }
 
 func (app *appEnv) postPage(w http.ResponseWriter, r *http.Request) {
 	app.logStart(r)
 
-	var userUpdate db.UpdatePageParams
-	if !app.readJSON(w, r, &userUpdate) {
+	var req struct {
+		db.UpdatePageParams
+		// SourceRefreshedAt is set when the editor refreshed the page from its source
+		SourceRefreshedAt pgtype.Timestamptz `json:"source_refreshed_at"`
+	}
+	if !app.readJSON(w, r, &req) {
 		return
 	}
+	userUpdate := req.UpdatePageParams
 
 	oldPage, err := app.svc.Queries.GetPageByID(r.Context(), userUpdate.ID)
 	if err != nil {
@@ -568,35 +606,108 @@ func (app *appEnv) postPage(w http.ResponseWriter, r *http.Request) {
 		app.replyErr(w, r, err)
 		return
 	}
+	// Don't let a page go live with invalid frontmatter
+	candidate := oldPage
+	if userUpdate.SetFrontmatter {
+		candidate.Frontmatter = userUpdate.Frontmatter
+	}
+	if userUpdate.SetScheduleFor {
+		candidate.ScheduleFor = userUpdate.ScheduleFor
+	}
+	if candidate.ShouldPublish() {
+		if err = candidate.Validate(); err != nil {
+			app.replyErr(w, r, err)
+			return
+		}
+	}
 	ctx := context.WithoutCancel(r.Context())
-	res, err := app.svc.Queries.UpdatePage(ctx, userUpdate)
+	var (
+		res      db.Page
+		warnings []string
+	)
+	// Save and publish together so a failed publish leaves no trace
+	err = app.svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		if res, txerr = txq.UpdatePageWithRevision(ctx, userUpdate); txerr != nil {
+			return txerr
+		}
+		// Saving refreshed content means the editor has seen the source change
+		if req.SourceRefreshedAt.Valid {
+			if txerr = txq.DeletePageSourceChange(ctx, db.DeletePageSourceChangeParams{
+				PageID:      res.ID,
+				RefreshedAt: req.SourceRefreshedAt.Time,
+			}); txerr != nil {
+				return txerr
+			}
+		}
+		if !res.ShouldPublish() {
+			return nil
+		}
+		// The page is still live at its old URL until it is republished
+		res.URLPath = oldPage.URLPath
+		txerr, warning := app.svc.PublishPage(ctx, txq, &res)
+		if warning != nil {
+			app.logErr(r.Context(), warning)
+			warnings = append(warnings, warning.Error())
+		}
+		return txerr
+	})
 	if err != nil {
-		err = fmt.Errorf("postPage update problem: %w", err)
+		if app.replyIfPageConflict(w, r, err, userUpdate) {
+			return
+		}
+		err = fmt.Errorf("postPage save problem: %w", err)
 		app.replyErr(w, r, err)
 		return
 	}
 	shouldPublish := res.ShouldPublish()
 	shouldNotify := res.ShouldNotify(&oldPage)
-	if shouldPublish {
-		err = app.svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
-			err, warning := app.svc.PublishPage(ctx, txq, &res)
-			if warning != nil {
-				app.logErr(r.Context(), warning)
-			}
-			return err
-		})
-		if err != nil {
-			err = fmt.Errorf("postPage publish problem: %w", err)
-			app.replyErr(w, r, err)
-			return
-		}
-	}
 	if shouldNotify {
 		if err = app.svc.Notify(ctx, &res, shouldPublish); err != nil {
 			app.logErr(ctx, err)
 		}
 	}
-	app.replyJSON(http.StatusOK, w, &res)
+	// Saving counts as a heartbeat.
+	// Anyone else still editing is returned so the client can warn about them.
+	editors, err := app.svc.HeartbeatPageLock(ctx, res.ID)
+	if err != nil {
+		app.logErr(ctx, err)
+	}
+	app.replyJSON(http.StatusOK, w, struct {
+		*db.Page
+		Editors  []db.PageLock `json:"editors"`
+		Warnings []string      `json:"warnings,omitempty"`
+	}{&res, editors, warnings})
+}
+
+func (app *appEnv) postPageLock(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req struct {
+		ID      int64 `json:"id,string"`
+		Release bool  `json:"release"`
+	}
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "id", req.ID, "release", req.Release)
+
+	if req.Release {
+		if err := app.svc.ReleasePageLock(r.Context(), req.ID); err != nil {
+			return app.jsonErr(err)
+		}
+		return app.jsonOK(struct {
+			Editors []db.PageLock `json:"editors"`
+		}{[]db.PageLock{}})
+	}
+	editors, err := app.svc.HeartbeatPageLock(r.Context(), req.ID)
+	if err != nil {
+		// Page may have been deleted
+		if db.IsForeignKeyViolation(err) {
+			return app.jsonNewErr(http.StatusNotFound, "could not find page ID %d", req.ID)
+		}
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(struct {
+		Editors []db.PageLock `json:"editors"`
+	}{editors})
 }
 
 func (app *appEnv) postPageJSON(w http.ResponseWriter, r *http.Request) {
@@ -609,22 +720,45 @@ func (app *appEnv) postPageJSON(w http.ResponseWriter, r *http.Request) {
 
 	ctx := context.WithoutCancel(r.Context())
 	var (
-		page *db.Page
-		err  error
+		page    *db.Page
+		err     error
+		warning error
 	)
 	err = app.svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) error {
-		page, err = app.svc.PublishJSONPage(ctx, txq, userUpdate)
+		page, err, warning = app.svc.PublishJSONPage(ctx, txq, userUpdate)
 		return err
 	})
 	if err != nil {
+		if app.replyIfPageConflict(w, r, err, userUpdate) {
+			return
+		}
 		err = fmt.Errorf("postPageJSON: publish problem: %w", err)
 		app.replyErr(w, r, err)
 		return
 	}
+	if warning != nil {
+		app.logErr(ctx, warning)
+	}
 
 	app.replyJSON(http.StatusOK, w, &page)
 }
 
+// replyIfPageConflict replies with 409 Conflict and the current page
+// if err came from an update whose expected_updated_at was stale.
+func (app *appEnv) replyIfPageConflict(w http.ResponseWriter, r *http.Request, err error, update db.UpdatePageParams) bool {
+	if !update.ExpectedUpdatedAt.Valid || !db.IsNotFound(err) {
+		return false
+	}
+	current, err := app.svc.Queries.GetPageByID(r.Context(), update.ID)
+	if err != nil {
+		return false
+	}
+	app.replyConflict(w, r, &current,
+		"Page was changed by someone else at %s. Reload to see their changes.",
+		timex.ToEST(current.UpdatedAt).Format("3:04pm"))
+	return true
+}
+
 func (app *appEnv) postPageRefresh(w http.ResponseWriter, r *http.Request) {
 	app.logStart(r)
 	var req struct {
@@ -655,9 +789,15 @@ func (app *appEnv) postPageRefresh(w http.ResponseWriter, r *http.Request) {
 			if page.Frontmatter == nil {
 				page.Frontmatter = make(db.Map)
 			}
+			authors, err := app.svc.MatchAuthorNames(r.Context(),
+				stringx.ExtractNames(dbDoc.Metadata.Byline))
+			if err != nil {
+				app.replyErr(w, r, err)
+				return
+			}
 			fm := map[string]any{
 				"byline":            dbDoc.Metadata.Byline,
-				"authors":           stringx.ExtractNames(dbDoc.Metadata.Byline),
+				"authors":           authors,
 				"title":             dbDoc.Metadata.Hed,
 				"description":       dbDoc.Metadata.Description,
 				"image":             dbDoc.Metadata.LedeImage,
@@ -686,7 +826,11 @@ func (app *appEnv) postPageRefresh(w http.ResponseWriter, r *http.Request) {
 			maps.Copy(page.Frontmatter, fm)
 		}
 		page.Body = dbDoc.ArticleMarkdown
-		app.replyJSON(http.StatusOK, w, &page)
+		// The editor sends this back on save to clear the source change warning
+		app.replyJSON(http.StatusOK, w, struct {
+			db.Page
+			SourceRefreshedAt time.Time `json:"source_refreshed_at"`
+		}{page, dbDoc.CreatedAt})
 		return
 	case "mailchimp":
 		app.replyNewErr(http.StatusConflict, w, r, "can not refresh source-type mailchimp; id=%d", id)
@@ -754,22 +898,27 @@ func (app *appEnv) siteDataGet(loc string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		app.logStart(r, "location", loc)
 
-		type response struct {
-			Configs []db.SiteDatum `json:"configs"`
-		}
-		var (
-			res response
-			err error
-		)
-		res.Configs, err = app.svc.Queries.GetSiteData(r.Context(), loc)
+		res, err := app.siteDataResponse(r.Context(), loc)
 		if err != nil {
 			app.replyErr(w, r, err)
 			return
 		}
+		w.Header().Set("ETag", res.ETag)
 		app.replyJSON(http.StatusOK, w, res)
 	}
 }
 
+type siteDataResponse struct {
+	Configs []db.SiteDatum `json:"configs"`
+	ETag    string         `json:"etag"`
+}
+
+func (app *appEnv) siteDataResponse(ctx context.Context, loc string) (res siteDataResponse, err error) {
+	res.Configs, err = app.svc.Queries.GetSiteData(ctx, loc)
+	res.ETag = almsvc.SiteConfigETag(res.Configs)
+	return
+}
+
 func (app *appEnv) getSiteData(w http.ResponseWriter, r *http.Request) http.Handler {
 	loc := r.URL.Query().Get("location")
 	return app.siteDataGet(loc)
@@ -789,19 +938,31 @@ func (app *appEnv) siteDataSet(loc string) http.HandlerFunc {
 			app.replyErr(w, r, resperr.E{M: "No schedulable items provided"})
 			return
 		}
+		if before, err := app.svc.Queries.GetSiteData(r.Context(), loc); err == nil {
+			almsvc.SetAuditTarget(r.Context(), loc, before)
+		}
 
 		var (
-			res struct {
-				Configs []db.SiteDatum `json:"configs"`
-			}
+			res siteDataResponse
 			err error
 		)
-		res.Configs, err = app.svc.UpdateSiteConfig(r.Context(), loc, req.Configs)
+		// Clients send back the ETag they loaded to avoid overwriting someone else's changes
+		ifMatch := r.Header.Get("If-Match")
+		res.Configs, err = app.svc.UpdateSiteConfig(r.Context(), loc, ifMatch, req.Configs)
+		if errors.Is(err, almsvc.ErrStaleSiteConfig) {
+			if current, err2 := app.siteDataResponse(r.Context(), loc); err2 == nil {
+				w.Header().Set("ETag", current.ETag)
+				app.replyConflict(w, r, current, "%s", resperr.UserMessage(err))
+				return
+			}
+		}
 		if err != nil {
 			app.replyErr(w, r, err)
 			return
 		}
-
+		almsvc.SetAuditAfter(r.Context(), res.Configs)
+		res.ETag = almsvc.SiteConfigETag(res.Configs)
+		w.Header().Set("ETag", res.ETag)
 		app.replyJSON(http.StatusOK, w, res)
 	}
 }
@@ -895,11 +1056,24 @@ func (app *appEnv) postSharedArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	before, err := app.svc.Queries.GetSharedArticleByID(r.Context(), req.ID)
+	if err != nil {
+		app.replyErr(w, r, db.NoRowsAs404(err, "missing shared_article %d", req.ID))
+		return
+	}
+	almsvc.SetAuditTarget(r.Context(), strconv.FormatInt(req.ID, 10), auditSharedArticle(before))
+
+	if err = app.svc.CheckSharingAllowed(r.Context(), &before, req.Status); err != nil {
+		app.replyErr(w, r, err)
+		return
+	}
+
 	article, err := app.svc.Queries.UpdateSharedArticle(r.Context(), req)
 	if err != nil {
 		app.replyErr(w, r, err)
 		return
 	}
+	almsvc.SetAuditAfter(r.Context(), auditSharedArticle(article))
 
 	app.replyJSON(http.StatusOK, w, &article)
 }
@@ -1017,20 +1191,15 @@ func (app *appEnv) postDonorWall(w http.ResponseWriter, r *http.Request) http.Ha
 	if err != nil {
 		return app.jsonErr(err)
 	}
-	paths := make([]string, 0, len(files))
-	for fpath := range files {
-		paths = append(paths, fpath)
-	}
-	err = flowmatic.Each(5, paths, func(fpath string) error {
-		msg := fmt.Sprintf("%s: updating donor wall", path.Base(fpath))
-		obj := files[fpath]
+	contents := make(map[string][]byte, len(files))
+	for fpath, obj := range files {
 		content, err := json.MarshalIndent(obj, "", "  ")
 		if err != nil {
-			return err
+			return app.jsonErr(err)
 		}
-		return app.svc.ContentStore.UpdateFile(r.Context(), msg, fpath, content)
-	})
-	if err != nil {
+		contents[fpath] = content
+	}
+	if err = app.svc.ContentStore.UpdateFiles(r.Context(), "Updating donor wall", contents); err != nil {
 		return app.jsonErr(err)
 	}
 	return app.jsonOK("OK")
@@ -1059,3 +1228,430 @@ func (app *appEnv) postPageLoad(w http.ResponseWriter, r *http.Request) http.Han
 	}
 	return app.jsonOK(page.ID)
 }
+
+func (app *appEnv) listPageRevisions(w http.ResponseWriter, r *http.Request) http.Handler {
+	var pageID int64
+	if !intFromQuery(r, "id", &pageID) {
+		return app.jsonNewErr(http.StatusBadRequest, "missing page ID")
+	}
+	var page int32
+	_ = intFromQuery(r, "page", &page)
+	app.logStart(r, "id", pageID, "page", page)
+	if page < 0 {
+		return app.jsonErr(resperr.E{M: "Invalid page"})
+	}
+
+	pager := paginate.PageNumber(page)
+	pager.PageSize = 50
+	revisions, err := paginate.List(pager, r.Context(),
+		app.svc.Queries.ListPageRevisions,
+		db.ListPageRevisionsParams{
+			PageID: pageID,
+			Limit:  pager.Limit(),
+			Offset: pager.Offset(),
+		})
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	if slices.Contains(r.URL.Query()["select"], "-body") {
+		for i := range revisions {
+			revisions[i].Body = ""
+			delete(revisions[i].Frontmatter, "raw-content")
+		}
+	}
+	return app.jsonOK(struct {
+		Revisions []db.PageRevision `json:"revisions"`
+		NextPage  int32             `json:"next_page,string,omitempty"`
+	}{
+		Revisions: revisions,
+		NextPage:  pager.NextPage,
+	})
+}
+
+func (app *appEnv) getPageRevisionDiff(w http.ResponseWriter, r *http.Request) http.Handler {
+	var fromID, toID int64
+	var v resperr.Validator
+	v.AddIf("from", !intFromQuery(r, "from", &fromID), "Missing revision ID")
+	v.AddIf("to", !intFromQuery(r, "to", &toID), "Missing revision ID")
+	app.logStart(r, "from", fromID, "to", toID)
+	if err := v.Err(); err != nil {
+		return app.jsonErr(err)
+	}
+
+	from, err := app.svc.Queries.GetPageRevisionByID(r.Context(), fromID)
+	if err != nil {
+		return app.jsonErr(db.NoRowsAs404(err, "could not find page revision %d", fromID))
+	}
+	to, err := app.svc.Queries.GetPageRevisionByID(r.Context(), toID)
+	if err != nil {
+		return app.jsonErr(db.NoRowsAs404(err, "could not find page revision %d", toID))
+	}
+	if from.PageID != to.PageID {
+		return app.jsonErr(resperr.E{M: "Revisions belong to different pages"})
+	}
+	return app.jsonOK(db.DiffPageRevisions(&from, &to))
+}
+
+func (app *appEnv) postPageRestore(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req struct {
+		RevisionID int64 `json:"revision_id,string"`
+	}
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "revision_id", req.RevisionID)
+
+	ctx := context.WithoutCancel(r.Context())
+	page, err, warning := app.svc.RestorePageRevision(ctx, req.RevisionID)
+	if warning != nil {
+		app.logErr(ctx, warning)
+	}
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(page)
+}
+
+func (app *appEnv) postPageUnpublish(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req struct {
+		ID         int64  `json:"id,string"`
+		RedirectTo string `json:"redirect_to"`
+	}
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "id", req.ID, "redirect_to", req.RedirectTo)
+
+	var v resperr.Validator
+	v.AddIf("redirect_to",
+		req.RedirectTo != "" &&
+			!strings.HasPrefix(req.RedirectTo, "/") &&
+			!strings.HasPrefix(req.RedirectTo, "https://"),
+		"Redirect must be a path or an https URL")
+	if err := v.Err(); err != nil {
+		return app.jsonErr(err)
+	}
+
+	ctx := context.WithoutCancel(r.Context())
+	page, err, warning := app.svc.UnpublishPage(ctx, req.ID, req.RedirectTo)
+	if warning != nil {
+		app.logErr(ctx, warning)
+	}
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(page)
+}
+
+func (app *appEnv) getJob(w http.ResponseWriter, r *http.Request) http.Handler {
+	var id int64
+	if !intFromQuery(r, "id", &id) {
+		return app.jsonNewErr(http.StatusBadRequest, "missing ID")
+	}
+	app.logStart(r, "id", id)
+
+	job, err := app.svc.Queries.GetJobByID(r.Context(), id)
+	if err != nil {
+		return app.jsonErr(db.NoRowsAs404(err, "could not find job %d", id))
+	}
+	return app.jsonOK(job)
+}
+
+func (app *appEnv) postTaxonomyRename(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req struct {
+		Taxonomy string `json:"taxonomy"`
+		From     string `json:"from"`
+		To       string `json:"to"`
+	}
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "taxonomy", req.Taxonomy, "from", req.From, "to", req.To)
+
+	job, err := app.svc.CreateTaxonomyJob(r.Context(), almsvc.TaxonomyChange{
+		Taxonomy: req.Taxonomy,
+		From:     []string{req.From},
+		To:       req.To,
+	}, false)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonAccepted(job)
+}
+
+func (app *appEnv) postTaxonomyMerge(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req almsvc.TaxonomyChange
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "taxonomy", req.Taxonomy, "from", req.From, "to", req.To)
+
+	job, err := app.svc.CreateTaxonomyJob(r.Context(), req, true)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonAccepted(job)
+}
+
+func (app *appEnv) postPageBulkEdit(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req almsvc.BulkEdit
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "file_path", req.Filter.FilePath, "apply", req.Apply)
+
+	job, err := app.svc.CreateBulkEditJob(r.Context(), req)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonAccepted(job)
+}
+
+func (app *appEnv) getLinkCheckReport(w http.ResponseWriter, r *http.Request) http.Handler {
+	app.logStart(r)
+
+	pages, err := app.svc.ListBrokenLinks(r.Context())
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(struct {
+		Pages []almsvc.BrokenLinkPage `json:"pages"`
+	}{pages})
+}
+
+func (app *appEnv) getPageRelated(w http.ResponseWriter, r *http.Request) http.Handler {
+	var id int64
+	if !intFromQuery(r, "id", &id) {
+		return app.jsonNewErr(http.StatusBadRequest, "missing page ID")
+	}
+	refresh, _ := boolFromQuery(r, "refresh")
+	app.logStart(r, "id", id, "refresh", refresh)
+
+	pages, computedAt, err := app.svc.ListRelatedPages(r.Context(), id, refresh)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(struct {
+		Pages      []almsvc.RelatedPage `json:"pages"`
+		ComputedAt time.Time            `json:"computed_at"`
+	}{pages, computedAt})
+}
+
+func (app *appEnv) postArchiveExport(w http.ResponseWriter, r *http.Request) http.Handler {
+	app.logStart(r)
+
+	job, err := app.svc.CreateArchiveExportJob(r.Context())
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonAccepted(job)
+}
+
+func (app *appEnv) postArchiveUpload(w http.ResponseWriter, r *http.Request) http.Handler {
+	app.logStart(r)
+
+	signedURL, path, err := app.svc.ArchiveUploadURL(r.Context())
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(struct {
+		SignedURL string `json:"signed_url"`
+		Path      string `json:"path"`
+	}{signedURL, path})
+}
+
+func (app *appEnv) postArchiveImport(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req almsvc.ArchiveImportRequest
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "path", req.Path, "dry_run", req.DryRun)
+
+	job, err := app.svc.CreateArchiveImportJob(r.Context(), req)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonAccepted(job)
+}
+
+func (app *appEnv) listPagesSearch(w http.ResponseWriter, r *http.Request) http.Handler {
+	q := r.URL.Query()
+	search := almsvc.PageSearch{
+		Query:      q.Get("query"),
+		Section:    q.Get("section"),
+		Author:     q.Get("author"),
+		Topic:      q.Get("topic"),
+		Series:     q.Get("series"),
+		State:      q.Get("state"),
+		SourceType: q.Get("source_type"),
+		Cursor:     q.Get("cursor"),
+	}
+	var err1, err2 error
+	search.PublishedAfter, err1 = timeFromQuery(r, "published_after")
+	search.PublishedBefore, err2 = timeFromQuery(r, "published_before")
+	if err := errors.Join(err1, err2); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "query", search.Query, "cursor", search.Cursor)
+
+	res, err := app.svc.SearchPages(r.Context(), search)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(res)
+}
+
+func (app *appEnv) listPageCorrections(w http.ResponseWriter, r *http.Request) http.Handler {
+	var pageID int64
+	if !intFromQuery(r, "page_id", &pageID) {
+		return app.jsonNewErr(http.StatusBadRequest, "missing page ID")
+	}
+	app.logStart(r, "page_id", pageID)
+
+	corrections, err := app.svc.Queries.ListPageCorrections(r.Context(), pageID)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	if corrections == nil {
+		corrections = []db.PageCorrection{}
+	}
+	return app.jsonOK(struct {
+		Corrections []db.PageCorrection `json:"corrections"`
+		Kinds       []string            `json:"kinds"`
+	}{corrections, almsvc.CorrectionKinds})
+}
+
+func (app *appEnv) postPageCorrection(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req struct {
+		ID          int64     `json:"id"`
+		PageID      int64     `json:"page_id"`
+		Kind        string    `json:"kind"`
+		Note        string    `json:"note"`
+		CorrectedAt time.Time `json:"corrected_at"`
+		Delete      bool      `json:"delete"`
+	}
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "id", req.ID, "page_id", req.PageID, "delete", req.Delete)
+
+	if req.Delete {
+		correction, err := app.svc.Queries.DeletePageCorrection(r.Context(), req.ID)
+		if err != nil {
+			return app.jsonErr(db.NoRowsAs404(err, "could not find correction %d", req.ID))
+		}
+		return app.jsonOK(correction)
+	}
+	correction, err := app.svc.SavePageCorrection(r.Context(), db.PageCorrection{
+		ID:          req.ID,
+		PageID:      req.PageID,
+		Kind:        req.Kind,
+		Note:        req.Note,
+		CorrectedAt: req.CorrectedAt,
+	})
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			return app.jsonNewErr(http.StatusNotFound, "could not find page ID %d", req.PageID)
+		}
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(correction)
+}
+
+func (app *appEnv) listAuthors(w http.ResponseWriter, r *http.Request) http.Handler {
+	app.logStart(r)
+
+	authors, err := app.svc.Queries.ListAuthors(r.Context())
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	if authors == nil {
+		authors = []db.ListAuthorsRow{}
+	}
+	return app.jsonOK(struct {
+		Authors []db.ListAuthorsRow `json:"authors"`
+	}{authors})
+}
+
+func (app *appEnv) postAuthor(w http.ResponseWriter, r *http.Request) http.Handler {
+	var req struct {
+		db.Author
+		Delete bool `json:"delete"`
+	}
+	if err := app.tryReadJSON(w, r, &req); err != nil {
+		return app.jsonErr(err)
+	}
+	app.logStart(r, "id", req.ID, "delete", req.Delete)
+
+	if req.Delete {
+		if err := app.svc.DeleteAuthor(r.Context(), req.ID); err != nil {
+			return app.jsonErr(err)
+		}
+		return app.jsonOK(req.Author)
+	}
+	author, err := app.svc.SaveAuthor(r.Context(), req.Author)
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			return app.jsonNewErr(http.StatusNotFound, "could not find photo ID %d", req.PhotoID.Int64)
+		}
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(author)
+}
+
+func (app *appEnv) getCalendar(w http.ResponseWriter, r *http.Request) http.Handler {
+	daysBefore, daysAfter := 7, 30
+	_ = intFromQuery(r, "days_before", &daysBefore)
+	_ = intFromQuery(r, "days_after", &daysAfter)
+	if daysBefore < 0 || daysAfter < 0 || daysBefore+daysAfter > 366 {
+		return app.jsonNewErr(http.StatusBadRequest, "invalid date range")
+	}
+	app.logStart(r, "days_before", daysBefore, "days_after", daysAfter)
+
+	now := time.Now()
+	const day = 24 * time.Hour
+	events, err := app.svc.Calendar(r.Context(),
+		now.Add(-time.Duration(daysBefore)*day),
+		now.Add(time.Duration(daysAfter)*day))
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	var feedURL string
+	if app.svc.CalendarToken != "" {
+		feedURL = almsvc.DeployURL + "/api/calendar.ics?token=" + url.QueryEscape(app.svc.CalendarToken)
+	}
+	return app.jsonOK(struct {
+		Events  []almsvc.CalendarEvent `json:"events"`
+		FeedURL string                 `json:"feed_url"`
+	}{events, feedURL})
+}
+
+// auditSharedArticle leaves the raw source data out of the audit log.
+func auditSharedArticle(article db.SharedArticle) db.SharedArticle {
+	article.RawData = nil
+	return article
+}
+
+func (app *appEnv) listAuditLog(w http.ResponseWriter, r *http.Request) http.Handler {
+	q := r.URL.Query()
+	query := almsvc.AuditLogQuery{
+		Email:  q.Get("email"),
+		Target: q.Get("target"),
+	}
+	var err1, err2 error
+	query.After, err1 = timeFromQuery(r, "after")
+	query.Before, err2 = timeFromQuery(r, "before")
+	if err := errors.Join(err1, err2); err != nil {
+		return app.jsonErr(err)
+	}
+	if q.Get("cursor") != "" && !intFromQuery(r, "cursor", &query.Cursor) {
+		return app.jsonNewErr(http.StatusBadRequest, "invalid cursor")
+	}
+	app.logStart(r, "email", query.Email, "target", query.Target, "cursor", query.Cursor)
+
+	res, err := app.svc.ListAuditEvents(r.Context(), query)
+	if err != nil {
+		return app.jsonErr(err)
+	}
+	return app.jsonOK(res)
+}
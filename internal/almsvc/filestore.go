@@ -3,6 +3,7 @@ package almsvc
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"strings"
 	"time"
@@ -53,3 +54,16 @@ func UploadJSON(ctx context.Context, is aws.BlobStore, filepath, cachecontrol st
 	h.Set("Cache-Control", cachecontrol)
 	return is.WriteFile(ctx, filepath, h, b)
 }
+
+// UploadXML is like UploadJSON, but it also reports whether the file changed.
+func UploadXML(ctx context.Context, is aws.BlobStore, filepath, cachecontrol string, data any) (changed bool, err error) {
+	b, err := xml.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	b = append([]byte(xml.Header), b...)
+	h := make(http.Header, 2)
+	h.Set("Content-Type", "application/xml")
+	h.Set("Cache-Control", cachecontrol)
+	return is.WriteFileIfChanged(ctx, filepath, h, b)
+}
@@ -25,6 +25,8 @@ func AddFlags(fl *flag.FlagSet) func() (svc Services, err error) {
 	isLambda := fl.Bool("lambda", false, "use AWS Lambda rather than HTTP")
 	mailchimpSignupURL := fl.String("mc-signup-url", "http://example.com", "`URL` to redirect users to for MailChimp signup")
 	netlifyHookSecret := fl.String("netlify-webhook-secret", "", "`shared secret` to authorize Netlify identity webhook")
+	calendarToken := fl.String("calendar-token", "", "`shared secret` to authorize the editorial calendar feed")
+	blockUnresolved := fl.Bool("block-unresolved-gdocs", false, "refuse to share Google Docs with unresolved suggestions or comments")
 	newsfeed := jsonfeed.AddFlags(fl)
 	anfService := anf.AddFlags(fl)
 
@@ -62,6 +64,8 @@ func AddFlags(fl *flag.FlagSet) func() (svc Services, err error) {
 			Auth:                 netlifyid.NewService(*isLambda),
 			MailchimpSignupURL:   *mailchimpSignupURL,
 			NetlifyWebhookSecret: *netlifyHookSecret,
+			CalendarToken:        *calendarToken,
+			BlockUnresolvedGDocs: *blockUnresolved,
 			Client:               &client,
 			DB:                   dbhandle,
 			Queries:              dbhandle.Queries(),
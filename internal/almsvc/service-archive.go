@@ -0,0 +1,452 @@
+package almsvc
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/earthboundkid/crockford/v2"
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/httpx"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+const (
+	// archiveBatchSize is the number of pages read from the database at a time
+	archiveBatchSize = 100
+	// archiveMaxFileSize is the largest file an import will read
+	archiveMaxFileSize = 10 << 20
+	// archiveDir is where archives are kept in the file store
+	archiveDir = "archives/"
+)
+
+// CreateArchiveExportJob queues a job that saves an export to the file store.
+// A full site takes too long to export within a request,
+// and is too large to send back from one.
+func (svc Services) CreateArchiveExportJob(ctx context.Context) (job db.Job, err error) {
+	return svc.CreateJob(ctx, JobArchiveExport, struct{}{})
+}
+
+func (svc Services) runArchiveExport(ctx context.Context, jp *JobProgress) (err error) {
+	defer errorx.Trace(&err)
+
+	var buf bytes.Buffer
+	if err = svc.ExportArchive(ctx, &buf); err != nil {
+		return err
+	}
+	filename := archiveName()
+	name := archiveDir + filename
+	h := make(http.Header, 2)
+	h.Set("Content-Type", "application/gzip")
+	h.Set("Content-Disposition", httpx.AttachmentName(filename))
+	if err = svc.FileStore.WriteFile(ctx, name, h, buf.Bytes()); err != nil {
+		return err
+	}
+	jp.SetResult(db.Map{
+		"path": name,
+		"url":  svc.FileStore.BuildURL(name),
+		"size": buf.Len(),
+	})
+	return nil
+}
+
+// archiveName returns a file name for an archive.
+// Archives only hold published content,
+// but the random part keeps them from being guessed in the file store.
+func archiveName() string {
+	return fmt.Sprintf("almanack-%s-%s.tar.gz",
+		timex.ToEST(time.Now()).Format("2006-01-02-150405"),
+		crockford.Random(crockford.Lower))
+}
+
+// ExportArchive writes a tar.gz of every published page and the current site data
+// to w, laid out like the Hugo repo.
+// Files have the same contents they were published with.
+func (svc Services) ExportArchive(ctx context.Context, w io.Writer) (err error) {
+	defer errorx.Trace(&err)
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	var afterID int64
+	for {
+		pages, err := svc.Queries.ListPublishedPagesAfterID(ctx, db.ListPublishedPagesAfterIDParams{
+			AfterID: afterID,
+			Limit:   archiveBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+		for _, page := range pages {
+			data, err := archivePageContent(&page)
+			if err != nil {
+				return fmt.Errorf("page %q: %w", page.FilePath, err)
+			}
+			if err = writeArchiveFile(tw, page.FilePath, data, page.LastPublished.Time); err != nil {
+				return err
+			}
+			afterID = page.ID
+		}
+		if len(pages) < archiveBatchSize {
+			break
+		}
+	}
+	locs, err := svc.Queries.ListSiteKeys(ctx)
+	if err != nil {
+		return err
+	}
+	for _, loc := range locs {
+		configs, err := svc.Queries.GetSiteData(ctx, loc)
+		if err != nil {
+			return err
+		}
+		config := currentSiteData(configs)
+		if config == nil {
+			continue
+		}
+		data, err := json.MarshalIndent(config.Data, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err = writeArchiveFile(tw, loc, data, config.ScheduleFor); err != nil {
+			return err
+		}
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// currentSiteData returns the config that is live on the site:
+// the last one scheduled before now, or else the first one.
+// configs must be sorted by schedule, as GetSiteData returns them.
+func currentSiteData(configs []db.SiteDatum) *db.SiteDatum {
+	if len(configs) == 0 {
+		return nil
+	}
+	current := &configs[0]
+	now := time.Now()
+	for i := range configs {
+		if configs[i].ScheduleFor.Before(now) {
+			current = &configs[i]
+		}
+	}
+	return current
+}
+
+// archivePageContent returns the page as it is published.
+// YouTube pages are published as JSON and everything else as TOML.
+func archivePageContent(page *db.Page) ([]byte, error) {
+	if page.SourceType == "youtube" {
+		return page.ToJSON()
+	}
+	s, err := page.ToTOML()
+	return []byte(s), err
+}
+
+func writeArchiveFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     int64(len(data)),
+		Mode:     0o644,
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// ArchiveImport reports what an import changed, or would change for a dry run.
+type ArchiveImport struct {
+	DryRun    bool                `json:"dry_run"`
+	Pages     []ArchivePageChange `json:"pages"`
+	SiteData  []string            `json:"site_data"`
+	Unchanged int                 `json:"unchanged"`
+	// Skipped lists files that are not pages or site data
+	Skipped []string `json:"skipped"`
+}
+
+// ArchivePageChange describes the changes to one page from an import.
+type ArchivePageChange struct {
+	FilePath string `json:"file_path"`
+	// ID is zero for new pages
+	ID          int64                  `json:"id"`
+	Frontmatter []db.FrontmatterChange `json:"frontmatter"`
+	BodyChanged bool                   `json:"body_changed"`
+}
+
+// ArchiveUploadURL returns a signed URL for uploading an archive to the file store
+// and the path to pass to CreateArchiveImportJob once it is uploaded.
+func (svc Services) ArchiveUploadURL(ctx context.Context) (signedURL, name string, err error) {
+	defer errorx.Trace(&err)
+
+	name = archiveDir + "uploads/" + archiveName()
+	h := make(http.Header, 1)
+	h.Set("Content-Type", "application/gzip")
+	signedURL, err = svc.FileStore.SignPutURL(ctx, name, h)
+	return signedURL, name, err
+}
+
+// ArchiveImportRequest is an uploaded archive to import.
+type ArchiveImportRequest struct {
+	Path   string `json:"path"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// CreateArchiveImportJob queues a job that runs ImportArchive
+// on an archive uploaded with ArchiveUploadURL.
+// The job result is the ArchiveImport report.
+func (svc Services) CreateArchiveImportJob(ctx context.Context, req ArchiveImportRequest) (job db.Job, err error) {
+	defer errorx.Trace(&err)
+
+	var v resperr.Validator
+	v.AddIf("path", !strings.HasPrefix(req.Path, archiveDir+"uploads/") ||
+		!strings.HasSuffix(req.Path, ".tar.gz") ||
+		path.Clean(req.Path) != req.Path,
+		"path must be an uploaded archive")
+	if err = v.Err(); err != nil {
+		return job, err
+	}
+	return svc.CreateJob(ctx, JobArchiveImport, req)
+}
+
+func (svc Services) runArchiveImport(ctx context.Context, jp *JobProgress) (err error) {
+	defer errorx.Trace(&err)
+
+	var req ArchiveImportRequest
+	if err = jp.Params(&req); err != nil {
+		return err
+	}
+	data, err := svc.FileStore.ReadFile(ctx, req.Path)
+	if err != nil {
+		return err
+	}
+	report, err := svc.ImportArchive(ctx, bytes.NewReader(data), req.DryRun)
+	if err != nil {
+		return err
+	}
+	jp.SetResult(db.Map{
+		"dry_run":   report.DryRun,
+		"pages":     report.Pages,
+		"site_data": report.SiteData,
+		"unchanged": report.Unchanged,
+		"skipped":   report.Skipped,
+	})
+	return nil
+}
+
+// ImportArchive loads the pages and site data in a tar.gz made by ExportArchive.
+// Imported pages are saved as published,
+// but nothing is pushed to the content store,
+// since the archive already matches what was published.
+// Site data is scheduled for now and gets published by the next cron run.
+// If dryRun is set, nothing is saved.
+func (svc Services) ImportArchive(ctx context.Context, r io.Reader, dryRun bool) (report ArchiveImport, err error) {
+	defer errorx.Trace(&err)
+
+	report = ArchiveImport{
+		DryRun:   dryRun,
+		Pages:    []ArchivePageChange{},
+		SiteData: []string{},
+		Skipped:  []string{},
+	}
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return report, resperr.New(http.StatusBadRequest, "archive is not gzipped: %w", err)
+	}
+	tr := tar.NewReader(gr)
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) error {
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return resperr.New(http.StatusBadRequest, "could not read archive: %w", err)
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+			isPage := strings.HasPrefix(name, "content/") && path.Ext(name) == ".md"
+			isSiteData := path.Ext(name) == ".json" &&
+				(strings.HasPrefix(name, "data/") || strings.HasPrefix(name, "config/"))
+			if !isPage && !isSiteData {
+				report.Skipped = append(report.Skipped, hdr.Name)
+				continue
+			}
+			if hdr.Size > archiveMaxFileSize {
+				return resperr.New(http.StatusBadRequest,
+					"%q is too large: %d bytes", name, hdr.Size)
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			var changed bool
+			if isPage {
+				changed, err = svc.importArchivePage(ctx, txq, name, data, &report)
+			} else {
+				changed, err = importArchiveSiteData(ctx, txq, name, data, dryRun)
+				if changed {
+					report.SiteData = append(report.SiteData, name)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("%q: %w", name, err)
+			}
+			if !changed {
+				report.Unchanged++
+			}
+		}
+	})
+	return report, err
+}
+
+func (svc Services) importArchivePage(ctx context.Context, txq *db.Queries, name string, data []byte, report *ArchiveImport) (changed bool, err error) {
+	defer errorx.Trace(&err)
+
+	var imported db.Page
+	if err = imported.FromMD(string(data)); err != nil {
+		return false, resperr.New(http.StatusBadRequest, "could not parse page: %w", err)
+	}
+
+	page, err := txq.GetPageByFilePath(ctx, name)
+	switch {
+	case db.IsNotFound(err):
+		page = db.Page{
+			FilePath:   name,
+			SourceType: "archive",
+			SourceID:   name,
+		}
+		// JSON pages are YouTube videos
+		if id, _ := imported.Frontmatter["youtube-id"].(string); id != "" && bytes.HasPrefix(data, []byte("{")) {
+			page.SourceType = "youtube"
+			page.SourceID = "yt:video:" + id
+		}
+		report.Pages = append(report.Pages, ArchivePageChange{
+			FilePath:    name,
+			Frontmatter: db.DiffFrontmatter(db.Map{}, imported.Frontmatter),
+			BodyChanged: imported.Body != "",
+		})
+	case err != nil:
+		return false, err
+	default:
+		current, err := archivePageContent(&page)
+		if err != nil {
+			return false, err
+		}
+		if page.LastPublished.Valid && bytes.Equal(current, data) {
+			return false, nil
+		}
+		// Compare the frontmatter the way it is published,
+		// so that blank keys and date formats don't show up as changes
+		var published db.Page
+		if err = published.FromMD(string(current)); err != nil {
+			return false, err
+		}
+		before, err := normalizeFrontmatter(published.Frontmatter)
+		if err != nil {
+			return false, err
+		}
+		after, err := normalizeFrontmatter(imported.Frontmatter)
+		if err != nil {
+			return false, err
+		}
+		report.Pages = append(report.Pages, ArchivePageChange{
+			FilePath:    name,
+			ID:          page.ID,
+			Frontmatter: db.DiffFrontmatter(before, after),
+			BodyChanged: published.Body != imported.Body,
+		})
+	}
+	if report.DryRun {
+		return true, nil
+	}
+	if page.ID == 0 {
+		created, err := txq.CreatePage(ctx, db.CreatePageParams{
+			FilePath:   page.FilePath,
+			SourceType: page.SourceType,
+			SourceID:   page.SourceID,
+		})
+		if err != nil {
+			return false, err
+		}
+		page.ID = created.ID
+	}
+	page.Frontmatter = imported.Frontmatter
+	page.SetURLPath()
+	// Unlike Page.Save, an empty body in the archive clears the page body
+	_, err = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+		ID:               page.ID,
+		SetFrontmatter:   true,
+		Frontmatter:      imported.Frontmatter,
+		SetBody:          true,
+		Body:             imported.Body,
+		URLPath:          page.URLPath.String,
+		SetLastPublished: true,
+		ScheduleFor:      db.NullTime,
+	})
+	return true, err
+}
+
+// normalizeFrontmatter round trips m through JSON,
+// the way it is stored in the database.
+// Dates decoded from TOML don't compare as equal otherwise.
+func normalizeFrontmatter(m db.Map) (db.Map, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	var out db.Map
+	err = json.Unmarshal(b, &out)
+	return out, err
+}
+
+func importArchiveSiteData(ctx context.Context, txq *db.Queries, loc string, data []byte, dryRun bool) (changed bool, err error) {
+	defer errorx.Trace(&err)
+
+	var m db.Map
+	if err = json.Unmarshal(data, &m); err != nil {
+		return false, resperr.New(http.StatusBadRequest, "could not parse site data: %w", err)
+	}
+	imported, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	configs, err := txq.GetSiteData(ctx, loc)
+	if err != nil {
+		return false, err
+	}
+	if config := currentSiteData(configs); config != nil {
+		current, err := json.MarshalIndent(config.Data, "", "  ")
+		if err != nil {
+			return false, err
+		}
+		if bytes.Equal(current, imported) {
+			return false, nil
+		}
+	}
+	if dryRun {
+		return true, nil
+	}
+	return true, txq.UpsertSiteData(ctx, db.UpsertSiteDataParams{
+		Key:         loc,
+		Data:        m,
+		ScheduleFor: time.Now(),
+	})
+}
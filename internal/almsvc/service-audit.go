@@ -0,0 +1,175 @@
+package almsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// Limits on how much of a value is kept in the audit log.
+const (
+	auditMaxString = 200
+	auditMaxItems  = 20
+	auditMaxDepth  = 4
+)
+
+// AuditRecord collects what handlers know about the change a request made.
+type AuditRecord struct {
+	Target string
+	Before any
+	// After is the saved state. If no handler sets it,
+	// the request body is logged instead.
+	After any
+}
+
+type auditContextKey struct{}
+
+// NewAuditContext returns a context for a request being audited.
+func NewAuditContext(ctx context.Context) (context.Context, *AuditRecord) {
+	rec := new(AuditRecord)
+	return context.WithValue(ctx, auditContextKey{}, rec), rec
+}
+
+// SetAuditTarget records what a request is changing and its state beforehand.
+// It does nothing if the request isn't being audited.
+func SetAuditTarget(ctx context.Context, target string, before any) {
+	if rec, ok := ctx.Value(auditContextKey{}).(*AuditRecord); ok {
+		rec.Target = target
+		rec.Before = before
+	}
+}
+
+// SetAuditAfter records the state a request saved.
+// It does nothing if the request isn't being audited.
+func SetAuditAfter(ctx context.Context, after any) {
+	if rec, ok := ctx.Value(auditContextKey{}).(*AuditRecord); ok {
+		rec.After = after
+	}
+}
+
+// SummarizeForAudit converts v to JSON,
+// trimming long strings and lists and deeply nested values.
+// Byte slices are taken to be JSON already;
+// if they aren't, only their length is kept.
+func SummarizeForAudit(v any) json.RawMessage {
+	var data any
+	switch v := v.(type) {
+	case nil:
+		return json.RawMessage("null")
+	case []byte:
+		if len(v) == 0 {
+			return json.RawMessage("null")
+		}
+		if err := json.Unmarshal(v, &data); err != nil {
+			data = map[string]any{"bytes": len(v)}
+		}
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			data = map[string]any{"error": err.Error()}
+		} else if err = json.Unmarshal(b, &data); err != nil {
+			data = map[string]any{"error": err.Error()}
+		}
+	}
+	b, err := json.Marshal(summarizeValue(data, 0))
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return b
+}
+
+func summarizeValue(v any, depth int) any {
+	switch v := v.(type) {
+	case string:
+		if utf8.RuneCountInString(v) <= auditMaxString {
+			return v
+		}
+		return string([]rune(v)[:auditMaxString]) + "…"
+	case []any:
+		if depth >= auditMaxDepth {
+			return fmt.Sprintf("[%d items]", len(v))
+		}
+		n := min(len(v), auditMaxItems)
+		s := make([]any, 0, n+1)
+		for _, item := range v[:n] {
+			s = append(s, summarizeValue(item, depth+1))
+		}
+		if len(v) > n {
+			s = append(s, fmt.Sprintf("…%d more", len(v)-n))
+		}
+		return s
+	case map[string]any:
+		if depth >= auditMaxDepth {
+			return fmt.Sprintf("{%d keys}", len(v))
+		}
+		m := make(map[string]any, len(v))
+		for key, val := range v {
+			m[key] = summarizeValue(val, depth+1)
+		}
+		return m
+	}
+	return v
+}
+
+// AuditTargetFromJSON guesses the target of a request from the ID in its body.
+func AuditTargetFromJSON(body []byte) string {
+	var req struct {
+		ID any `json:"id"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	switch id := req.ID.(type) {
+	case string:
+		return id
+	case float64:
+		return fmt.Sprint(int64(id))
+	}
+	return ""
+}
+
+const auditLogLimit = 100
+
+type AuditLogQuery struct {
+	Email  string
+	Target string
+	After  time.Time
+	Before time.Time
+	Cursor int64
+}
+
+type AuditLogResult struct {
+	Events     []db.AuditEvent `json:"events"`
+	NextCursor int64           `json:"next_cursor"`
+}
+
+// ListAuditEvents lists the audit events matching q, newest first.
+func (svc Services) ListAuditEvents(ctx context.Context, q AuditLogQuery) (res AuditLogResult, err error) {
+	defer errorx.Trace(&err)
+
+	events, err := svc.Queries.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		EmailAddress:  q.Email,
+		Target:        q.Target,
+		CreatedAfter:  timestamptzOrNull(q.After),
+		CreatedBefore: timestamptzOrNull(q.Before),
+		CursorID:      q.Cursor,
+		Limit:         auditLogLimit + 1,
+	})
+	if err != nil {
+		return res, err
+	}
+	if len(events) > auditLogLimit {
+		events = events[:auditLogLimit]
+		res.NextCursor = events[len(events)-1].ID
+	}
+	if events == nil {
+		events = []db.AuditEvent{}
+	}
+	res.Events = events
+	return res, nil
+}
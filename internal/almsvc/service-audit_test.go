@@ -0,0 +1,50 @@
+package almsvc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+)
+
+func TestSummarizeForAudit(t *testing.T) {
+	cases := map[string]struct {
+		in   any
+		want string
+	}{
+		"nil":      {nil, `null`},
+		"empty":    {[]byte{}, `null`},
+		"not json": {[]byte("\x1f\x8b tarball"), `{"bytes":10}`},
+		"json":     {[]byte(`{"id": 1, "remove": true}`), `{"id":1,"remove":true}`},
+		"struct": {
+			struct {
+				Name string `json:"name"`
+			}{"x"},
+			`{"name":"x"}`,
+		},
+		"long string": {
+			strings.Repeat("a", auditMaxString+1),
+			`"` + strings.Repeat("a", auditMaxString) + `…"`,
+		},
+		"long list": {
+			make([]int, auditMaxItems+5),
+			`[` + strings.Repeat("0,", auditMaxItems) + `"…5 more"]`,
+		},
+		"deep": {
+			map[string]any{"a": map[string]any{"b": map[string]any{"c": map[string]any{"d": []int{1, 2}}}}},
+			`{"a":{"b":{"c":{"d":"[2 items]"}}}}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			be.Equal(t, tc.want, string(SummarizeForAudit(tc.in)))
+		})
+	}
+}
+
+func TestAuditTargetFromJSON(t *testing.T) {
+	be.Equal(t, "12", AuditTargetFromJSON([]byte(`{"id": 12}`)))
+	be.Equal(t, "abc", AuditTargetFromJSON([]byte(`{"id": "abc"}`)))
+	be.Equal(t, "", AuditTargetFromJSON([]byte(`{"page_id": 12}`)))
+	be.Equal(t, "", AuditTargetFromJSON([]byte(`[1, 2]`)))
+}
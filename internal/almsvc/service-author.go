@@ -0,0 +1,347 @@
+package almsvc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/stringx"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+const authorsDataPath = "data/authors.json"
+
+func authorPagePath(slug string) string {
+	return fmt.Sprintf("content/authors/%s/_index.md", slug)
+}
+
+// MatchAuthorNames replaces any names that belong to an author
+// with the author's name as it is spelled in the author table.
+// Names without an author are left as they are.
+func (svc Services) MatchAuthorNames(ctx context.Context, names []string) (matched []string, err error) {
+	defer errorx.Trace(&err)
+
+	if len(names) == 0 {
+		return names, nil
+	}
+	authors, err := svc.Queries.ListAuthorsByNames(ctx, authorNamesParams(names))
+	if err != nil {
+		return nil, err
+	}
+	matched = make([]string, 0, len(names))
+	for _, name := range names {
+		for _, author := range authors {
+			if author.Slug == stringx.SlugifyURL(name) || strings.EqualFold(author.Name, name) {
+				name = author.Name
+				break
+			}
+		}
+		matched = append(matched, name)
+	}
+	return matched, nil
+}
+
+func authorNamesParams(names []string) db.ListAuthorsByNamesParams {
+	var arg db.ListAuthorsByNamesParams
+	for _, name := range names {
+		arg.Slugs = append(arg.Slugs, stringx.SlugifyURL(name))
+		arg.Names = append(arg.Names, strings.ToLower(name))
+	}
+	return arg
+}
+
+// SaveAuthor creates author, or updates it if it has an ID.
+// The author data file is republished,
+// along with the author's profile page if it has been published.
+// Profile pages are otherwise created when a page by the author is published.
+func (svc Services) SaveAuthor(ctx context.Context, author db.Author) (saved db.Author, err error) {
+	defer errorx.Trace(&err)
+
+	author.Name = strings.TrimSpace(author.Name)
+	author.Slug = strings.TrimSpace(author.Slug)
+	if author.Slug == "" {
+		author.Slug = stringx.SlugifyURL(author.Name)
+	}
+	author.Email = strings.TrimSpace(author.Email)
+	if author.SocialLinks == nil {
+		author.SocialLinks = db.Map{}
+	}
+	var v resperr.Validator
+	v.AddIf("name", author.Name == "", "name is required")
+	v.AddIf("slug", author.Slug != stringx.SlugifyURL(author.Slug),
+		"slug may only contain lowercase letters, numbers, and dashes")
+	if author.Email != "" {
+		_, mailErr := mail.ParseAddress(author.Email)
+		v.AddIf("email", mailErr != nil, "email %q is invalid", author.Email)
+	}
+	for network, link := range author.SocialLinks {
+		s, _ := link.(string)
+		u, urlErr := url.Parse(s)
+		v.AddIf("social_links", urlErr != nil || (u.Scheme != "http" && u.Scheme != "https"),
+			"link for %s must be a URL", network)
+	}
+	if err = v.Err(); err != nil {
+		return saved, err
+	}
+
+	if author.ID != 0 {
+		old, err := svc.Queries.GetAuthorByID(ctx, author.ID)
+		if err != nil {
+			return saved, db.NoRowsAs404(err, "could not find author %d", author.ID)
+		}
+		if old.Slug != author.Slug {
+			_, err = svc.Queries.GetPageByFilePath(ctx, authorPagePath(old.Slug))
+			switch {
+			case err == nil:
+				return saved, resperr.New(http.StatusConflict,
+					"cannot change the slug of %s after their author page was created", old.Name)
+			case !db.IsNotFound(err):
+				return saved, err
+			}
+		}
+	}
+
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		if author.ID == 0 {
+			saved, txerr = txq.CreateAuthor(ctx, db.CreateAuthorParams{
+				Name:        author.Name,
+				Slug:        author.Slug,
+				Title:       author.Title,
+				Bio:         author.Bio,
+				PhotoID:     author.PhotoID,
+				Email:       author.Email,
+				SocialLinks: author.SocialLinks,
+				Active:      author.Active,
+			})
+		} else {
+			saved, txerr = txq.UpdateAuthor(ctx, db.UpdateAuthorParams{
+				ID:          author.ID,
+				Name:        author.Name,
+				Slug:        author.Slug,
+				Title:       author.Title,
+				Bio:         author.Bio,
+				PhotoID:     author.PhotoID,
+				Email:       author.Email,
+				SocialLinks: author.SocialLinks,
+				Active:      author.Active,
+			})
+		}
+		if db.IsUniquenessViolation(txerr, "author_slug_key") {
+			return resperr.New(http.StatusConflict,
+				"another author already uses the slug %q", author.Slug)
+		}
+		if txerr != nil {
+			return txerr
+		}
+
+		files := make(map[string][]byte)
+		if txerr = stageAuthorsData(ctx, txq, files); txerr != nil {
+			return txerr
+		}
+		if txerr = svc.stageAuthorPage(ctx, txq, saved.Slug, files); txerr != nil {
+			return txerr
+		}
+		msg := fmt.Sprintf("Content: updating author %q", saved.Name)
+		return svc.ContentStore.UpdateFiles(ctx, msg, files)
+	})
+	return saved, err
+}
+
+// DeleteAuthor removes an author who was added by mistake.
+// Authors with a profile page should be marked inactive instead.
+func (svc Services) DeleteAuthor(ctx context.Context, id int64) (err error) {
+	defer errorx.Trace(&err)
+
+	return svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		author, txerr := txq.DeleteAuthor(ctx, id)
+		if txerr != nil {
+			return db.NoRowsAs404(txerr, "could not find author %d", id)
+		}
+		_, txerr = txq.GetPageByFilePath(ctx, authorPagePath(author.Slug))
+		switch {
+		case txerr == nil:
+			return resperr.New(http.StatusConflict,
+				"%s has an author page; mark them inactive instead", author.Name)
+		case !db.IsNotFound(txerr):
+			return txerr
+		}
+
+		files := make(map[string][]byte)
+		if txerr = stageAuthorsData(ctx, txq, files); txerr != nil {
+			return txerr
+		}
+		msg := fmt.Sprintf("Content: removing author %q", author.Name)
+		return svc.ContentStore.UpdateFiles(ctx, msg, files)
+	})
+}
+
+type authorData struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Bio         string `json:"bio"`
+	Photo       string `json:"photo"`
+	Email       string `json:"email"`
+	SocialLinks db.Map `json:"social-links"`
+	Active      bool   `json:"active"`
+}
+
+// stageAuthorsData adds the data file of every author, keyed by slug, to files.
+func stageAuthorsData(ctx context.Context, txq *db.Queries, files map[string][]byte) (err error) {
+	defer errorx.Trace(&err)
+
+	authors, err := txq.ListAuthors(ctx)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]authorData, len(authors))
+	for _, author := range authors {
+		data[author.Slug] = authorData{
+			Name:        author.Name,
+			Slug:        author.Slug,
+			Title:       author.Title,
+			Bio:         author.Bio,
+			Photo:       author.PhotoPath,
+			Email:       author.Email,
+			SocialLinks: author.SocialLinks,
+			Active:      author.Active,
+		}
+	}
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	files[authorsDataPath] = b
+	return nil
+}
+
+// stageAuthorPage refreshes the profile page of the author with slug
+// if it has been published and adds its content file to files.
+func (svc Services) stageAuthorPage(ctx context.Context, txq *db.Queries, slug string, files map[string][]byte) (err error) {
+	defer errorx.Trace(&err)
+
+	page, err := txq.GetPageByFilePath(ctx, authorPagePath(slug))
+	if db.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !page.LastPublished.Valid {
+		return nil
+	}
+	authors, err := txq.ListAuthorsByNames(ctx, db.ListAuthorsByNamesParams{
+		Slugs: []string{slug},
+	})
+	if err != nil {
+		return err
+	}
+	for _, author := range authors {
+		if author.Slug == slug {
+			setAuthorPage(&page, author)
+		}
+	}
+	if page, err = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+		ID:             page.ID,
+		SetFrontmatter: true,
+		Frontmatter:    page.Frontmatter,
+		SetBody:        true,
+		Body:           page.Body,
+		ScheduleFor:    db.NullTime,
+	}); err != nil {
+		return err
+	}
+	_, err, warning := svc.stagePagePublish(ctx, txq, &page, files)
+	if warning != nil {
+		almlog.FromContext(ctx).WarnContext(ctx, "stageAuthorPage", "slug", slug, "warning", warning)
+	}
+	return err
+}
+
+// ensureAuthorPages creates profile pages for any authors of page
+// that don't have one yet and adds their content files to files.
+func (svc Services) ensureAuthorPages(ctx context.Context, txq *db.Queries, page *db.Page, files map[string][]byte) (err error) {
+	defer errorx.Trace(&err)
+
+	names := page.Authors()
+	if len(names) == 0 {
+		return nil
+	}
+	authors, err := txq.ListAuthorsByNames(ctx, authorNamesParams(names))
+	if err != nil {
+		return err
+	}
+	for _, author := range authors {
+		path := authorPagePath(author.Slug)
+		_, err = txq.GetPageByFilePath(ctx, path)
+		switch {
+		case err == nil:
+			continue
+		case !db.IsNotFound(err):
+			return err
+		}
+
+		profile := &db.Page{
+			FilePath:   path,
+			SourceType: "author",
+			SourceID:   author.Slug,
+			Frontmatter: db.Map{
+				"published": timex.ToEST(time.Now()),
+			},
+		}
+		setAuthorPage(profile, author)
+		if err = profile.Save(ctx, txq, true); err != nil {
+			return err
+		}
+		var data string
+		if data, err = profile.ToTOML(); err != nil {
+			return err
+		}
+		files[profile.FilePath] = []byte(data)
+	}
+	return nil
+}
+
+// setAuthorPage fills in the profile page of author,
+// keeping any other frontmatter set by editors.
+func setAuthorPage(page *db.Page, author db.ListAuthorsByNamesRow) {
+	if page.Frontmatter == nil {
+		page.Frontmatter = db.Map{}
+	}
+	fm := db.Map{
+		"title":        author.Name,
+		"linktitle":    author.Name,
+		"slug":         author.Slug,
+		"author-title": author.Title,
+		"image":        author.PhotoPath,
+		"email":        author.Email,
+	}
+	for key, val := range fm {
+		if val == "" {
+			delete(page.Frontmatter, key)
+		} else {
+			page.Frontmatter[key] = val
+		}
+	}
+	if len(author.SocialLinks) == 0 {
+		delete(page.Frontmatter, "social-links")
+	} else {
+		page.Frontmatter["social-links"] = map[string]any(author.SocialLinks)
+	}
+	page.Frontmatter["inactive"] = !author.Active
+	page.Body = author.Bio
+}
@@ -0,0 +1,38 @@
+package almsvc
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestSetAuthorPage(t *testing.T) {
+	page := db.Page{
+		Frontmatter: db.Map{
+			"email":        "old@example.com",
+			"layout":       "staff",
+			"social-links": map[string]any{"x": "https://x.com/old"},
+		},
+	}
+	setAuthorPage(&page, db.ListAuthorsByNamesRow{
+		Name:      "Jo Smith",
+		Slug:      "jo-smith",
+		Title:     "Reporter",
+		Bio:       "Jo covers *Harrisburg*.",
+		PhotoPath: "2025/01/jo.jpeg",
+		Active:    false,
+	})
+	be.Equal(t, "Jo Smith", page.Frontmatter["title"])
+	be.Equal(t, "jo-smith", page.Frontmatter["slug"])
+	be.Equal(t, "Reporter", page.Frontmatter["author-title"])
+	be.Equal(t, "2025/01/jo.jpeg", page.Frontmatter["image"])
+	be.Equal(t, true, page.Frontmatter["inactive"])
+	be.Equal(t, "Jo covers *Harrisburg*.", page.Body)
+	// Editor fields are kept, but cleared author fields are removed
+	be.Equal(t, "staff", page.Frontmatter["layout"])
+	_, hasEmail := page.Frontmatter["email"]
+	be.False(t, hasEmail)
+	_, hasLinks := page.Frontmatter["social-links"]
+	be.False(t, hasLinks)
+}
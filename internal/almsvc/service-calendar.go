@@ -0,0 +1,195 @@
+package almsvc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// The calendar feed covers the past month and the next three months.
+const (
+	CalendarFeedPast   = 30 * 24 * time.Hour
+	CalendarFeedFuture = 90 * 24 * time.Hour
+)
+
+// scheduleConflictWindow is how close together two pages
+// in the same section can be scheduled before the desk is warned.
+const scheduleConflictWindow = 5 * time.Minute
+
+var siteDataAdminPaths = map[string]string{
+	HomepageLoc:     "/admin/editors-picks",
+	SidebarLoc:      "/admin/sidebar-items",
+	SiteParamsLoc:   "/admin/site-params",
+	StateCollegeLoc: "/admin/state-college-editor",
+	BerksLoc:        "/admin/berks-editor",
+}
+
+type CalendarEvent struct {
+	Kind      string    `json:"kind"`
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Section   string    `json:"section,omitempty"`
+	At        time.Time `json:"at"`
+	AdminPath string    `json:"admin_path"`
+	Warning   string    `json:"warning,omitempty"`
+}
+
+// Calendar lists the editorial events between start and end:
+// scheduled and published pages, scheduled site data changes,
+// and shared article embargoes.
+// Pages scheduled close together in the same section get a warning.
+func (svc Services) Calendar(ctx context.Context, start, end time.Time) (events []CalendarEvent, err error) {
+	defer errorx.Trace(&err)
+
+	rows, err := svc.Queries.ListCalendarEvents(ctx, db.ListCalendarEventsParams{
+		StartsAt: start,
+		EndsAt:   end,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events = make([]CalendarEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, newCalendarEvent(row))
+	}
+	warnScheduleConflicts(events)
+	return events, nil
+}
+
+func newCalendarEvent(row db.ListCalendarEventsRow) CalendarEvent {
+	event := CalendarEvent{
+		Kind:  row.Kind,
+		ID:    row.ID,
+		Title: row.Title,
+		At:    row.At,
+	}
+	switch row.Kind {
+	case "scheduled-page", "published-page":
+		event.Section = pageSection(row.Key)
+		event.AdminPath = fmt.Sprintf("/admin/news/%d", row.ID)
+		if event.Section == "content/topics/" || event.Section == "content/series/" {
+			event.AdminPath = fmt.Sprintf("/admin/landing/%d", row.ID)
+		}
+	case "site-data":
+		event.Title = MessageForLoc(row.Key)
+		event.AdminPath = siteDataAdminPaths[row.Key]
+		if event.AdminPath == "" {
+			event.AdminPath = "/admin"
+		}
+	case "embargo":
+		event.Title = "Embargo lifts: " + row.Title
+		event.AdminPath = fmt.Sprintf("/admin/shared-articles/%d", row.ID)
+	}
+	return event
+}
+
+// pageSection returns the top directory of a page's file path,
+// like content/news/.
+func pageSection(filePath string) string {
+	dir, rest, ok := strings.Cut(strings.TrimPrefix(filePath, "content/"), "/")
+	if !ok || rest == "" {
+		return "content/"
+	}
+	return "content/" + dir + "/"
+}
+
+// warnScheduleConflicts marks scheduled pages that will be published
+// within scheduleConflictWindow of another page in the same section.
+// Events must be sorted by time.
+func warnScheduleConflicts(events []CalendarEvent) {
+	lastInSection := make(map[string]int)
+	for i := range events {
+		event := &events[i]
+		if event.Kind != "scheduled-page" {
+			continue
+		}
+		if j, ok := lastInSection[event.Section]; ok {
+			prev := &events[j]
+			if event.At.Sub(prev.At) < scheduleConflictWindow {
+				event.Warning = fmt.Sprintf("Scheduled within %s of %q", scheduleConflictWindow, prev.Title)
+				if prev.Warning == "" {
+					prev.Warning = fmt.Sprintf("Scheduled within %s of %q", scheduleConflictWindow, event.Title)
+				}
+			}
+		}
+		lastInSection[event.Section] = i
+	}
+}
+
+// WriteCalendarICS writes events as an iCalendar feed
+// with links back to the admin at baseURL.
+func WriteCalendarICS(w io.Writer, events []CalendarEvent, baseURL string, now time.Time) error {
+	var ics icsWriter
+	ics.line("BEGIN", "VCALENDAR")
+	ics.line("VERSION", "2.0")
+	ics.line("PRODID", "-//Spotlight PA//Almanack//EN")
+	ics.line("CALSCALE", "GREGORIAN")
+	ics.line("METHOD", "PUBLISH")
+	ics.line("X-WR-CALNAME", icsEscape("Spotlight PA editorial calendar"))
+	for _, event := range events {
+		link := baseURL + event.AdminPath
+		summary := event.Title
+		description := link
+		if event.Warning != "" {
+			summary = "⚠️ " + summary
+			description = event.Warning + "\n" + description
+		}
+		ics.line("BEGIN", "VEVENT")
+		ics.line("UID", fmt.Sprintf("%s-%d@almanack.spotlightpa.org", event.Kind, event.ID))
+		ics.line("DTSTAMP", icsTime(now))
+		ics.line("DTSTART", icsTime(event.At))
+		ics.line("DTEND", icsTime(event.At.Add(15*time.Minute)))
+		ics.line("SUMMARY", icsEscape(summary))
+		ics.line("DESCRIPTION", icsEscape(description))
+		ics.line("URL", link)
+		if event.Section != "" {
+			ics.line("CATEGORIES", icsEscape(event.Section))
+		}
+		ics.line("END", "VEVENT")
+	}
+	ics.line("END", "VCALENDAR")
+	_, err := io.WriteString(w, ics.String())
+	return err
+}
+
+type icsWriter struct {
+	strings.Builder
+}
+
+// line writes a content line, folding it at 75 octets as RFC 5545 requires.
+func (ics *icsWriter) line(name, value string) {
+	s := name + ":" + value
+	n := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if n+size > 75 {
+			ics.WriteString("\r\n ")
+			n = 1
+		}
+		ics.WriteRune(r)
+		n += size
+	}
+	ics.WriteString("\r\n")
+}
+
+var icsEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
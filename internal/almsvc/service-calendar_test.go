@@ -0,0 +1,65 @@
+package almsvc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+)
+
+func TestPageSection(t *testing.T) {
+	for in, want := range map[string]string{
+		"content/news/a.md":                "content/news/",
+		"content/statecollege/2024/a.md":   "content/statecollege/",
+		"content/topics/housing/_index.md": "content/topics/",
+		"content/about.md":                 "content/",
+	} {
+		be.Equal(t, want, pageSection(in))
+	}
+}
+
+func TestWarnScheduleConflicts(t *testing.T) {
+	at := time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)
+	events := []CalendarEvent{
+		{Kind: "scheduled-page", Title: "A", Section: "content/news/", At: at},
+		{Kind: "scheduled-page", Title: "B", Section: "content/berks/", At: at.Add(time.Minute)},
+		{Kind: "site-data", Title: "C", At: at.Add(2 * time.Minute)},
+		{Kind: "scheduled-page", Title: "D", Section: "content/news/", At: at.Add(4 * time.Minute)},
+		{Kind: "scheduled-page", Title: "E", Section: "content/news/", At: at.Add(10 * time.Minute)},
+	}
+	warnScheduleConflicts(events)
+	be.In(t, `"D"`, events[0].Warning)
+	be.Zero(t, events[1].Warning)
+	be.Zero(t, events[2].Warning)
+	be.In(t, `"A"`, events[3].Warning)
+	be.Zero(t, events[4].Warning)
+}
+
+func TestWriteCalendarICS(t *testing.T) {
+	now := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	events := []CalendarEvent{{
+		Kind:      "scheduled-page",
+		ID:        1,
+		Title:     "Budget, taxes; and " + strings.Repeat("more ", 20),
+		Section:   "content/news/",
+		At:        time.Date(2024, 5, 1, 10, 30, 0, 0, time.FixedZone("EDT", -4*60*60)),
+		AdminPath: "/admin/news/1",
+		Warning:   "Too close",
+	}}
+	var buf strings.Builder
+	be.NilErr(t, WriteCalendarICS(&buf, events, "https://example.com", now))
+	ics := buf.String()
+	be.In(t, "BEGIN:VCALENDAR\r\n", ics)
+	be.In(t, "UID:scheduled-page-1@almanack.spotlightpa.org\r\n", ics)
+	be.In(t, "DTSTART:20240501T143000Z\r\n", ics)
+	be.In(t, "DTEND:20240501T144500Z\r\n", ics)
+	be.In(t, `SUMMARY:⚠️ Budget\, taxes\; and`, ics)
+	be.In(t, `DESCRIPTION:Too close\nhttps://example.com/admin/news/1`, ics)
+	be.In(t, "END:VCALENDAR\r\n", ics)
+	for line := range strings.SplitSeq(ics, "\r\n") {
+		be.True(t, len(line) <= 75)
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	be.In(t, strings.Repeat("more ", 20), unfolded)
+}
@@ -38,6 +38,21 @@ func intermediateDocToMarkdown(doc *html.Node) string {
 			Data: data,
 		})
 	}
+	// Write notes as shortcodes for the theme to style
+	for dataEl, value := range dataEls(doc, dtFootnotes) {
+		notes := []string{"{{<footnotes>}}"}
+		for i, li := range footnoteItems(value) {
+			body := strings.TrimSpace(blocko.Blockize(li))
+			notes = append(notes,
+				shortcode.New("footnote", "n", strconv.Itoa(i+1))+"\n"+
+					body+"\n{{</footnote>}}")
+		}
+		notes = append(notes, "{{</footnotes>}}")
+		xhtml.ReplaceWith(dataEl, &html.Node{
+			Type: html.RawNode,
+			Data: strings.Join(notes, "\n\n"),
+		})
+	}
 	for dataEl, value := range dataEls(doc, dtDBEmbed) {
 		dbembed := dbEmbedFromString(value)
 		switch dbembed.Type {
@@ -102,6 +117,40 @@ func intermediateDocToMarkdown(doc *html.Node) string {
 				Type: html.RawNode,
 				Data: shortcode.New(tag, stringx.FlattenMap(attrs)...),
 			})
+		case db.PullquoteEmbedTag:
+			quote := dbembed.Value.(db.EmbedPullquote)
+			xhtml.ReplaceWith(dataEl, &html.Node{
+				Type: html.RawNode,
+				Data: shortcode.New("pullquote",
+					"quote", quote.Quote,
+					"attribution", quote.Attribution,
+				),
+			})
+		// Callouts wrap their body as Markdown
+		case db.CalloutEmbedTag:
+			callout := dbembed.Value.(db.EmbedCallout)
+			var attrs []string
+			if callout.Title != "" {
+				attrs = []string{"title", callout.Title}
+			}
+			container := xhtml.New("div")
+			must.Do(xhtml.SetInnerHTML(container, callout.Body))
+			xhtml.ReplaceWith(dataEl, &html.Node{
+				Type: html.RawNode,
+				Data: shortcode.New(callout.Kind, attrs...) + "\n" +
+					strings.TrimSpace(blocko.Blockize(container)) + "\n" +
+					"{{</" + callout.Kind + ">}}",
+			})
+		case db.RelatedEmbedTag:
+			related := dbembed.Value.(db.EmbedRelated)
+			xhtml.ReplaceWith(dataEl, &html.Node{
+				Type: html.RawNode,
+				Data: shortcode.New("related",
+					"url", related.URL,
+					"hed", related.Hed,
+					"image", related.Image,
+				),
+			})
 		default:
 			panic("unknown embed type: " + dbembed.Type)
 		}
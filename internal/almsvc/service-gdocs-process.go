@@ -31,6 +31,7 @@ const (
 	dtSpotlightText dataTagType = "spl-text"
 	dtPartnerText   dataTagType = "partner-text"
 	dtDBEmbed       dataTagType = "db-embed"
+	dtFootnotes     dataTagType = "footnotes"
 )
 
 func newDataTag(dtype dataTagType, value string) *html.Node {
@@ -185,6 +186,71 @@ func createIntermediateDoc(docHTML *html.Node) (
 				xhtml.ReplaceWith(tbl, data)
 			}
 
+		case "pullquote", "pull quote":
+			quote := db.EmbedPullquote{
+				Quote: cmp.Or(
+					xhtml.TextContent(rows.Value("quote")),
+					xhtml.TextContent(rows.At(1, 0)),
+				),
+				Attribution: cmp.Or(
+					xhtml.TextContent(rows.Value("attribution")),
+					xhtml.TextContent(rows.Value("by")),
+					xhtml.TextContent(rows.Value("source")),
+				),
+			}
+			if quote.Quote == "" {
+				warnings = append(warnings, fmt.Sprintf(
+					"Table %d missing pullquote text", n,
+				))
+				tbl.Parent.RemoveChild(tbl)
+				break
+			}
+			embed.Type = db.PullquoteEmbedTag
+			embed.Value = quote
+			goto append
+
+		case "callout", "factbox", "fact box":
+			kind := "callout"
+			if label != "callout" {
+				kind = "factbox"
+			}
+			body := cmp.Or(
+				rows.Value("body"),
+				rows.Value("text"),
+			)
+			if xhtml.TextContent(body) == "" {
+				warnings = append(warnings, fmt.Sprintf(
+					"Table %d missing %s body", n, kind,
+				))
+				tbl.Parent.RemoveChild(tbl)
+				break
+			}
+			bodyEl := must.Get(blocko.Minify(xhtml.ToBuffer(body)))
+			blocko.MergeSiblings(bodyEl)
+			blocko.RemoveEmptyP(bodyEl)
+			blocko.RemoveMarks(bodyEl)
+			embed.Type = db.CalloutEmbedTag
+			embed.Value = db.EmbedCallout{
+				Kind: kind,
+				Title: cmp.Or(
+					xhtml.TextContent(rows.Value("title")),
+					xhtml.TextContent(rows.Value("hed")),
+				),
+				Body: xhtml.InnerHTMLBlocks(bodyEl),
+			}
+			goto append
+
+		case "related", "related story":
+			related, warning := processRelated(tbl, rows, n)
+			if warning != "" {
+				warnings = append(warnings, warning)
+				tbl.Parent.RemoveChild(tbl)
+				break
+			}
+			embed.Type = db.RelatedEmbedTag
+			embed.Value = *related
+			goto append
+
 		case "metadata", "info":
 			processMetadata(rows, &metadata)
 			tbl.Parent.RemoveChild(tbl)
@@ -215,6 +281,22 @@ func createIntermediateDoc(docHTML *html.Node) (
 		n++
 	}
 
+	// Drop notes whose reference went with an embed table or the ### tail
+	if section := xhtml.Select(docHTML, isFootnotesSection); section != nil &&
+		!renumberFootnotes(docHTML, section) {
+		section.Parent.RemoveChild(section)
+	}
+
+	// Save the notes section for each output to render
+	if section := xhtml.Select(docHTML, isFootnotesSection); section != nil {
+		notes := must.Get(blocko.Minify(xhtml.ToBuffer(section)))
+		blocko.MergeSiblings(notes)
+		blocko.RemoveEmptyP(notes)
+		blocko.RemoveMarks(notes)
+		data := newDataTag(dtFootnotes, xhtml.InnerHTML(notes))
+		xhtml.ReplaceWith(section, data)
+	}
+
 	intermediateDoc = must.Get(blocko.Minify(xhtml.ToBuffer(docHTML)))
 
 	blocko.MergeSiblings(intermediateDoc)
@@ -263,6 +345,85 @@ func createIntermediateDoc(docHTML *html.Node) (
 	return
 }
 
+func isFootnotesSection(n *html.Node) bool {
+	return n.DataAtom == atom.Section && xhtml.Attr(n, "role") == "doc-endnotes"
+}
+
+func isFootnoteRef(n *html.Node) bool {
+	return n.DataAtom == atom.Sup && strings.HasPrefix(xhtml.Attr(n, "id"), "fnref-")
+}
+
+// renumberFootnotes removes the notes in section that are no longer referenced in doc
+// and numbers the rest in the order they are referenced.
+// It reports whether any notes are left.
+func renumberFootnotes(doc, section *html.Node) bool {
+	nums := make(map[string]int)
+	for sup := range xhtml.SelectAll(doc, isFootnoteRef) {
+		old := strings.TrimPrefix(xhtml.Attr(sup, "id"), "fnref-")
+		num, ok := nums[old]
+		if !ok {
+			num = len(nums) + 1
+			nums[old] = num
+		}
+		xhtml.SetAttr(sup, "id", fmt.Sprintf("fnref-%d", num))
+		if link := xhtml.Select(sup, xhtml.WithAtom(atom.A)); link != nil {
+			xhtml.SetAttr(link, "href", fmt.Sprintf("#fn-%d", num))
+			xhtml.RemoveAll(slices.Collect(link.ChildNodes()))
+			xhtml.AppendText(link, strconv.Itoa(num))
+		}
+	}
+	ol := xhtml.Select(section, xhtml.WithAtom(atom.Ol))
+	if ol == nil {
+		return false
+	}
+	var orphans []*html.Node
+	for li := range ol.ChildNodes() {
+		old := strings.TrimPrefix(xhtml.Attr(li, "id"), "fn-")
+		if num, ok := nums[old]; ok {
+			xhtml.SetAttr(li, "id", fmt.Sprintf("fn-%d", num))
+		} else {
+			orphans = append(orphans, li)
+		}
+	}
+	xhtml.RemoveAll(orphans)
+	return ol.FirstChild != nil
+}
+
+// footnoteItems returns the <li> notes saved in a footnotes data tag.
+func footnoteItems(value string) []*html.Node {
+	container := xhtml.New("div")
+	must.Do(xhtml.SetInnerHTML(container, value))
+	ol := xhtml.Select(container, xhtml.WithAtom(atom.Ol))
+	if ol == nil {
+		return nil
+	}
+	return slices.Collect(ol.ChildNodes())
+}
+
+// footnotesToHTML renders saved notes as a headed list
+// with links back to where each note is referenced.
+func footnotesToHTML(value string) *html.Node {
+	section := xhtml.New("section", "role", "doc-endnotes")
+	h2 := xhtml.New("h2")
+	xhtml.AppendText(h2, "Notes")
+	section.AppendChild(h2)
+	ol := xhtml.New("ol")
+	section.AppendChild(ol)
+	for i, li := range footnoteItems(value) {
+		li.Parent.RemoveChild(li)
+		backlink := xhtml.New("a",
+			"href", fmt.Sprintf("#fnref-%d", i+1),
+			"role", "doc-backlink",
+		)
+		xhtml.AppendText(backlink, "↩")
+		last := xhtml.LastChildOrNew(li, "p")
+		xhtml.AppendText(last, " ")
+		last.AppendChild(backlink)
+		ol.AppendChild(li)
+	}
+	return section
+}
+
 func processImage(rows tableaux.TableNodes, n int, kind string) (imageEmbed *db.EmbedImage, warning string) {
 	var width, height int
 	if w := xhtml.TextContent(rows.Value("width")); w != "" {
@@ -293,6 +454,44 @@ func processImage(rows tableaux.TableNodes, n int, kind string) (imageEmbed *db.
 	)
 }
 
+// relatedURL finds the link in a related table,
+// either as the value of a url row or a link in the table.
+func relatedURL(tbl *html.Node, rows tableaux.TableNodes) string {
+	if u := cmp.Or(
+		xhtml.TextContent(rows.Value("url")),
+		xhtml.TextContent(rows.Value("link")),
+	); u != "" {
+		return u
+	}
+	return xhtml.Attr(xhtml.Select(tbl, xhtml.WithAtom(atom.A)), "href")
+}
+
+func processRelated(tbl *html.Node, rows tableaux.TableNodes, n int) (related *db.EmbedRelated, warning string) {
+	u := relatedURL(tbl, rows)
+	link, internal, ok := db.NormalizeLink(u)
+	if !ok {
+		return nil, fmt.Sprintf("Table %d has a bad related link: %q", n, u)
+	}
+	if internal {
+		link = "https://www.spotlightpa.org" + link
+	}
+	related = &db.EmbedRelated{
+		URL: link,
+		Hed: cmp.Or(
+			xhtml.TextContent(rows.Value("hed")),
+			xhtml.TextContent(rows.Value("title")),
+		),
+		Image: cmp.Or(
+			xhtml.TextContent(rows.Value("image")),
+			xhtml.TextContent(rows.Value("path")),
+		),
+	}
+	if related.Hed == "" {
+		return nil, fmt.Sprintf("Table %d related link %q has no hed", n, u)
+	}
+	return related, ""
+}
+
 func processMetadata(rows tableaux.TableNodes, metadata *db.GDocsMetadata) {
 	metadata.InternalID = cmp.Or(
 		xhtml.TextContent(rows.Value("slug")),
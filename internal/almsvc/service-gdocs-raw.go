@@ -26,6 +26,9 @@ func intermediateDocToPartnerHTML(doc *html.Node) *html.Node {
 			Data: text,
 		})
 	}
+	for dataEl, value := range dataEls(rawHTML, dtFootnotes) {
+		xhtml.ReplaceWith(dataEl, footnotesToHTML(value))
+	}
 	for dataEl, value := range dataEls(rawHTML, dtDBEmbed) {
 		dbembed := dbEmbedFromString(value)
 		switch dbembed.Type {
@@ -45,6 +48,12 @@ func intermediateDocToPartnerHTML(doc *html.Node) *html.Node {
 				Type: html.RawNode,
 				Data: dbembed.Value.(string),
 			})
+		case db.PullquoteEmbedTag:
+			xhtml.ReplaceWith(dataEl, pullquoteToHTML(dbembed.Value.(db.EmbedPullquote)))
+		case db.CalloutEmbedTag:
+			xhtml.ReplaceWith(dataEl, calloutToHTML(dbembed.Value.(db.EmbedCallout)))
+		case db.RelatedEmbedTag:
+			xhtml.ReplaceWith(dataEl, relatedToHTML(dbembed.Value.(db.EmbedRelated)))
 		default:
 			panic("unknown embed type: " + dbembed.Type)
 		}
@@ -54,3 +63,43 @@ func intermediateDocToPartnerHTML(doc *html.Node) *html.Node {
 	}
 	return rawHTML
 }
+
+func pullquoteToHTML(quote db.EmbedPullquote) *html.Node {
+	figure := xhtml.New("figure", "class", "pullquote")
+	blockquote := xhtml.New("blockquote")
+	p := xhtml.New("p")
+	xhtml.AppendText(p, quote.Quote)
+	blockquote.AppendChild(p)
+	figure.AppendChild(blockquote)
+	if quote.Attribution != "" {
+		caption := xhtml.New("figcaption")
+		xhtml.AppendText(caption, quote.Attribution)
+		figure.AppendChild(caption)
+	}
+	return figure
+}
+
+func calloutToHTML(callout db.EmbedCallout) *html.Node {
+	aside := xhtml.New("aside", "class", callout.Kind)
+	if callout.Title != "" {
+		h3 := xhtml.New("h3")
+		xhtml.AppendText(h3, callout.Title)
+		aside.AppendChild(h3)
+	}
+	aside.AppendChild(&html.Node{
+		Type: html.RawNode,
+		Data: callout.Body,
+	})
+	return aside
+}
+
+func relatedToHTML(related db.EmbedRelated) *html.Node {
+	aside := xhtml.New("aside", "class", "related")
+	p := xhtml.New("p")
+	xhtml.AppendText(p, "Related: ")
+	link := xhtml.New("a", "href", related.URL)
+	xhtml.AppendText(link, related.Hed)
+	p.AppendChild(link)
+	aside.AppendChild(p)
+	return aside
+}
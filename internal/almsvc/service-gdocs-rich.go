@@ -26,13 +26,61 @@ func intermediateDocToPartnerRichText(doc *html.Node) *html.Node {
 			Data: text,
 		})
 	}
-	// Replace other embeds with red placeholder text
+	// Keep notes as a heading and list
+	for dataEl, value := range dataEls(richText, dtFootnotes) {
+		section := footnotesToHTML(value)
+		xhtml.ReplaceWith(dataEl, section)
+		xhtml.UnnestChildren(section)
+	}
 	for dataEl, value := range dataEls(richText, dtDBEmbed) {
 		dbembed := dbEmbedFromString(value)
-		if imgTag, ok := dbembed.Value.(db.EmbedImage); ok && imgTag.Kind == "spl" {
-			dataEl.Parent.RemoveChild(dataEl)
+		switch v := dbembed.Value.(type) {
+		case db.EmbedImage:
+			if v.Kind == "spl" {
+				dataEl.Parent.RemoveChild(dataEl)
+				continue
+			}
+		// Spell out text embeds so they can be pasted as is
+		case db.EmbedPullquote:
+			blockquote := xhtml.New("blockquote")
+			p := xhtml.New("p")
+			xhtml.AppendText(p, v.Quote)
+			blockquote.AppendChild(p)
+			if v.Attribution != "" {
+				p = xhtml.New("p")
+				xhtml.AppendText(p, "— "+v.Attribution)
+				blockquote.AppendChild(p)
+			}
+			xhtml.ReplaceWith(dataEl, blockquote)
+			continue
+		case db.EmbedCallout:
+			container := xhtml.New("div")
+			container.AppendChild(xhtml.New("hr"))
+			if v.Title != "" {
+				h3 := xhtml.New("h3")
+				xhtml.AppendText(h3, v.Title)
+				container.AppendChild(h3)
+			}
+			container.AppendChild(&html.Node{
+				Type: html.RawNode,
+				Data: v.Body,
+			})
+			container.AppendChild(xhtml.New("hr"))
+			xhtml.ReplaceWith(dataEl, container)
+			xhtml.UnnestChildren(container)
+			continue
+		case db.EmbedRelated:
+			p := xhtml.New("p")
+			strong := xhtml.New("strong")
+			xhtml.AppendText(strong, "Related: ")
+			p.AppendChild(strong)
+			link := xhtml.New("a", "href", v.URL)
+			xhtml.AppendText(link, v.Hed)
+			p.AppendChild(link)
+			xhtml.ReplaceWith(dataEl, p)
 			continue
 		}
+		// Replace other embeds with red placeholder text
 		placeholder := xhtml.New("h2", "style", "color: red;")
 		xhtml.AppendText(placeholder, fmt.Sprintf("Embed #%d", dbembed.N))
 		xhtml.ReplaceWith(dataEl, placeholder)
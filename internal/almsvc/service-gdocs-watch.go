@@ -0,0 +1,81 @@
+package almsvc
+
+import (
+	"context"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// gdocsWatchLookback is how far back WatchGDocs asks Drive for edits.
+// Cron runs every few minutes, so this covers a few missed runs.
+const gdocsWatchLookback = time.Hour
+
+// WatchGDocs imports a new copy of each watched Google Doc
+// that has been edited since it was last imported
+// and flags the pages made from it as having a changed source.
+// The new copies are left for ProcessGDocs.
+// Shared articles keep pointing at the copy that was reviewed.
+func (svc Services) WatchGDocs(ctx context.Context) (err error) {
+	defer errorx.Trace(&err)
+
+	docs, err := svc.Queries.ListGDocsWatched(ctx)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+	if err = svc.ConfigureGoogleCert(ctx); err != nil {
+		return err
+	}
+	cl, err := svc.Gsvc.GDocsClient(ctx)
+	if err != nil {
+		return err
+	}
+	edited, err := svc.Gsvc.ModifiedDocs(ctx, cl, time.Now().Add(-gdocsWatchLookback))
+	if err != nil {
+		return err
+	}
+
+	l := almlog.FromContext(ctx)
+	for _, doc := range docs {
+		modified, ok := edited[doc.ExternalID]
+		if !ok || !modified.After(doc.LastImportedAt) {
+			continue
+		}
+		l.InfoContext(ctx, "WatchGDocs: reimporting",
+			"external_id", doc.ExternalID,
+			"modified", modified,
+			"last_imported", doc.LastImportedAt)
+		dbDoc, err := svc.CreateGDocsDoc(ctx, doc.ExternalID)
+		if err != nil {
+			return err
+		}
+		if _, err = svc.Queries.UpsertPageSourceChanges(ctx, db.UpsertPageSourceChangesParams{
+			ChangedAt:  dbDoc.CreatedAt,
+			SourceType: "gdocs",
+			SourceID:   doc.ExternalID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PageSourceChangedAt returns when the watcher last saw the source of a page change
+// since the page was saved, or nil if it hasn't.
+func (svc Services) PageSourceChangedAt(ctx context.Context, pageID int64) (changedAt *time.Time, err error) {
+	defer errorx.Trace(&err)
+
+	change, err := svc.Queries.GetPageSourceChange(ctx, pageID)
+	if db.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &change.ChangedAt, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/carlmjohnson/requests"
 	"github.com/earthboundkid/crockford/v2"
 	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
 	"github.com/earthboundkid/xhtml"
 	"github.com/spotlightpa/almanack/internal/almlog"
 	"github.com/spotlightpa/almanack/internal/convert/blocko"
@@ -64,6 +65,7 @@ func (svc Services) CreateGDocsDoc(ctx context.Context, externalID string) (dbDo
 	newDoc, err := svc.Queries.CreateGDocsDoc(ctx, db.CreateGDocsDocParams{
 		ExternalID: externalID,
 		Document:   *doc,
+		Unresolved: svc.findUnresolvedGDocsEdits(ctx, cl, externalID),
 	})
 	if err != nil {
 		return nil, err
@@ -71,6 +73,73 @@ func (svc Services) CreateGDocsDoc(ctx context.Context, externalID string) (dbDo
 	return &newDoc, nil
 }
 
+// findUnresolvedGDocsEdits describes the pending suggestions
+// and open comment threads in a document.
+// Problems checking are logged rather than blocking the import.
+func (svc Services) findUnresolvedGDocsEdits(ctx context.Context, cl *http.Client, externalID string) []string {
+	const excerptLength = 80
+	l := almlog.FromContext(ctx)
+	notes := []string{}
+
+	doc, err := gdocs.RequestWithSuggestions(ctx, cl, externalID)
+	if err != nil {
+		l.ErrorContext(ctx, "findUnresolvedGDocsEdits: RequestWithSuggestions", "err", err)
+	} else {
+		for _, s := range gdocs.Suggestions(doc) {
+			verb := "insert"
+			if s.Kind == "deletion" {
+				verb = "delete"
+			}
+			notes = append(notes, fmt.Sprintf("Unresolved suggestion to %s %q.",
+				verb, stringx.Truncate(s.Text, excerptLength)))
+		}
+	}
+
+	comments, err := svc.Gsvc.Comments(ctx, cl, externalID)
+	if err != nil {
+		l.ErrorContext(ctx, "findUnresolvedGDocsEdits: Comments", "err", err)
+	}
+	for _, c := range comments {
+		author := cmp.Or(c.Author.DisplayName, "unknown")
+		content := stringx.Truncate(c.Content, excerptLength)
+		if quote := c.QuotedFileContent.Value; quote != "" {
+			notes = append(notes, fmt.Sprintf("Open comment by %s on %q: %q.",
+				author, stringx.Truncate(quote, excerptLength), content))
+		} else {
+			notes = append(notes, fmt.Sprintf("Open comment by %s: %q.", author, content))
+		}
+	}
+	return notes
+}
+
+// CheckSharingAllowed returns an error if article is being shared
+// while its Google Doc has unresolved suggestions or comments
+// and sharing such documents is blocked.
+func (svc Services) CheckSharingAllowed(ctx context.Context, article *db.SharedArticle, status string) (err error) {
+	defer errorx.Trace(&err)
+
+	if !svc.BlockUnresolvedGDocs ||
+		article.SourceType != "gdocs" ||
+		status == article.Status ||
+		(status != "P" && status != "S") {
+		return nil
+	}
+	var id int64
+	if err = json.Unmarshal(article.RawData, &id); err != nil {
+		return err
+	}
+	doc, err := svc.Queries.GetGDocsByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if n := len(doc.Unresolved); n > 0 {
+		return resperr.New(http.StatusConflict,
+			"cannot share %q until its %d unresolved suggestions and comments are resolved and the document is refreshed",
+			article.InternalID, n)
+	}
+	return nil
+}
+
 func (svc Services) ProcessGDocs(ctx context.Context) error {
 	docs, err := svc.Queries.ListGDocsWhereUnprocessed(ctx)
 	if err != nil {
@@ -106,6 +175,7 @@ func (svc Services) ProcessGDocsDoc(ctx context.Context, dbDoc db.GDocsDoc) (err
 
 	metadata, embeds, _, richText, rawHTML, md, warnings2 := processDocHTML(docHTML)
 	warnings = append(warnings, warnings2...)
+	warnings = append(warnings, dbDoc.Unresolved...)
 
 	// Default slug is article title
 	metadata.InternalID = cmp.Or(metadata.InternalID, dbDoc.Document.Title)
@@ -146,6 +216,10 @@ func removeTail(n *html.Node) {
 
 		remove := []*html.Node{c}
 		for sibling := c.NextSibling; sibling != nil; sibling = sibling.NextSibling {
+			// Footnotes are added after the body, so keep them
+			if isFootnotesSection(sibling) {
+				continue
+			}
 			remove = append(remove, sibling)
 		}
 		xhtml.RemoveAll(remove)
@@ -176,6 +250,11 @@ func (svc Services) processDocExternals(ctx context.Context, dbDoc *db.GDocsDoc,
 				warnings = append(warnings, warning)
 			}
 
+		case "related", "related story":
+			if err := svc.fillRelatedFromPage(ctx, tbl, rows); err != nil {
+				return nil, err
+			}
+
 		case "metadata", "info":
 			if warning := svc.replaceMetadataImagePath(
 				ctx, tbl, rows, dbDoc.ExternalID, objID2Path,
@@ -252,6 +331,44 @@ func (svc Services) replaceImagePath(
 	return ""
 }
 
+// fillRelatedFromPage adds the hed and image of the page
+// a related table links to, unless they were set in the document.
+func (svc Services) fillRelatedFromPage(ctx context.Context, tbl *html.Node, rows tableaux.TableNodes) error {
+	hed := cmp.Or(
+		xhtml.TextContent(rows.Value("hed")),
+		xhtml.TextContent(rows.Value("title")),
+	)
+	image := cmp.Or(
+		xhtml.TextContent(rows.Value("image")),
+		xhtml.TextContent(rows.Value("path")),
+	)
+	if hed != "" && image != "" {
+		return nil
+	}
+	upath, internal, ok := db.NormalizeLink(relatedURL(tbl, rows))
+	if !ok || !internal {
+		return nil
+	}
+	page, err := svc.Queries.GetPageByURLPath(ctx, upath)
+	if db.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if hed == "" {
+		if title, _ := page.Frontmatter["title"].(string); title != "" {
+			setRowValue(tbl, "hed", title)
+		}
+	}
+	if image == "" {
+		if path, _ := page.Frontmatter["image"].(string); path != "" {
+			setRowValue(tbl, "image", path)
+		}
+	}
+	return nil
+}
+
 func setRowValue(tbl *html.Node, key, value string) {
 	tr := xhtml.New("tr")
 	keyNode := xhtml.New("td")
@@ -1,9 +1,13 @@
 package almsvc
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/carlmjohnson/be"
+	"github.com/earthboundkid/xhtml"
+	"github.com/spotlightpa/almanack/internal/convert/blocko"
+	"github.com/spotlightpa/almanack/internal/utils/must"
 )
 
 func TestImageCAS(t *testing.T) {
@@ -29,3 +33,21 @@ func TestImageCAS(t *testing.T) {
 		}
 	}
 }
+
+func TestRemoveTail(t *testing.T) {
+	doc := must.Get(blocko.Minify(strings.NewReader(
+		`<p>Body</p><p>###</p><p>Notes to editors</p>` +
+			`<section role="doc-endnotes"><ol><li><p>Source</p></li></ol></section>`)))
+	removeTail(doc)
+	be.Equal(t,
+		`<p>Body</p><section role="doc-endnotes"><ol><li><p>Source</p></li></ol></section>`,
+		xhtml.InnerHTML(doc))
+
+	// Notes only referenced in the tail are dropped
+	doc = must.Get(blocko.Minify(strings.NewReader(
+		`<p>Body</p><p>###</p><p>Notes<sup id="fnref-1"><a href="#fn-1">1</a></sup></p>` +
+			`<section role="doc-endnotes"><ol><li id="fn-1"><p>Source</p></li></ol></section>`)))
+	removeTail(doc)
+	_, _, _, intDoc := createIntermediateDoc(doc)
+	be.Equal(t, `<p>Body</p>`, xhtml.InnerHTML(intDoc))
+}
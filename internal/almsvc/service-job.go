@@ -0,0 +1,156 @@
+package almsvc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+)
+
+// Job kinds
+const (
+	JobArchiveExport  = "archive-export"
+	JobArchiveImport  = "archive-import"
+	JobPageBulkEdit   = "page-bulk-edit"
+	JobPageRelated    = "page-related"
+	JobTaxonomyRename = "taxonomy-rename"
+	JobTaxonomyMerge  = "taxonomy-merge"
+)
+
+// jobRunners maps job kinds to the method that runs them.
+var jobRunners = map[string]func(Services, context.Context, *JobProgress) error{
+	JobArchiveExport:  Services.runArchiveExport,
+	JobArchiveImport:  Services.runArchiveImport,
+	JobPageBulkEdit:   Services.runPageBulkEdit,
+	JobPageRelated:    Services.runPageRelated,
+	JobTaxonomyRename: Services.runTaxonomyRename,
+	JobTaxonomyMerge:  Services.runTaxonomyMerge,
+}
+
+// CreateJob queues a job of kind to be run by RunPendingJobs.
+func (svc Services) CreateJob(ctx context.Context, kind string, params any) (job db.Job, err error) {
+	defer errorx.Trace(&err)
+
+	if _, ok := jobRunners[kind]; !ok {
+		return job, fmt.Errorf("unknown job kind %q", kind)
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return job, err
+	}
+	var m db.Map
+	if err = json.Unmarshal(b, &m); err != nil {
+		return job, err
+	}
+	return svc.Queries.CreateJob(ctx, db.CreateJobParams{
+		Kind:      kind,
+		Params:    m,
+		CreatedBy: netlifyid.FromContext(ctx).Email(),
+	})
+}
+
+// RunPendingJobs runs queued jobs one at a time until none are left.
+func (svc Services) RunPendingJobs(ctx context.Context) error {
+	var errs []error
+	for {
+		job, err := svc.Queries.PopPendingJob(ctx)
+		if db.IsNotFound(err) {
+			return errors.Join(errs...)
+		}
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		errs = append(errs, svc.runJob(ctx, &job))
+	}
+}
+
+func (svc Services) runJob(ctx context.Context, job *db.Job) (err error) {
+	defer errorx.Trace(&err)
+
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "Services.runJob: starting", "id", job.ID, "kind", job.Kind)
+
+	// Attribute revisions and commits to whoever queued the job
+	ctx = netlifyid.NewContext(ctx, &netlifyid.JWT{
+		User: netlifyid.User{Email: job.CreatedBy},
+	})
+	jp := &JobProgress{svc: svc, job: job}
+	if run, ok := jobRunners[job.Kind]; ok {
+		err = run(svc, ctx, jp)
+	} else {
+		err = fmt.Errorf("unknown job kind %q", job.Kind)
+	}
+	var errMsg string
+	if err != nil {
+		errMsg = err.Error()
+	}
+	if job.Result == nil {
+		job.Result = db.Map{}
+	}
+	// Record the outcome even if the job ran out of time
+	finished, finishErr := svc.Queries.FinishJob(context.WithoutCancel(ctx), db.FinishJobParams{
+		ID:     job.ID,
+		Result: job.Result,
+		Error:  errMsg,
+	})
+	if finishErr != nil {
+		return errors.Join(err, finishErr)
+	}
+	*job = finished
+	l.InfoContext(ctx, "Services.runJob: finished", "id", job.ID, "kind", job.Kind, "err", err)
+	return err
+}
+
+// JobProgress lets a running job report its progress.
+type JobProgress struct {
+	svc Services
+	job *db.Job
+}
+
+// Params decodes the job's parameters into dst.
+func (jp *JobProgress) Params(dst any) error {
+	b, err := json.Marshal(jp.job.Params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// SetResult sets the result that is saved when the job finishes.
+func (jp *JobProgress) SetResult(result db.Map) {
+	jp.job.Result = result
+}
+
+// SetTotal sets the number of items the job expects to process.
+func (jp *JobProgress) SetTotal(ctx context.Context, total int) error {
+	jp.job.Total = int32(total)
+	return jp.save(ctx)
+}
+
+// Advance marks n more items as processed and records any non-nil warnings.
+func (jp *JobProgress) Advance(ctx context.Context, n int, warnings ...error) error {
+	jp.job.Processed += int32(n)
+	for _, warning := range warnings {
+		if warning != nil {
+			jp.job.Warnings = append(jp.job.Warnings, warning.Error())
+		}
+	}
+	return jp.save(ctx)
+}
+
+func (jp *JobProgress) save(ctx context.Context) error {
+	if jp.job.Warnings == nil {
+		jp.job.Warnings = []string{}
+	}
+	return jp.svc.Queries.UpdateJobProgress(ctx, db.UpdateJobProgressParams{
+		ID:        jp.job.ID,
+		Total:     jp.job.Total,
+		Processed: jp.job.Processed,
+		Warnings:  jp.job.Warnings,
+	})
+}
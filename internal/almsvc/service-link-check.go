@@ -0,0 +1,314 @@
+package almsvc
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/carlmjohnson/flowmatic"
+	"github.com/carlmjohnson/requests"
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/lazy"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+const (
+	// linkCheckMaxAge is how long a link check result is trusted
+	linkCheckMaxAge = 7 * 24 * time.Hour
+	// linkCheckHosts is the number of hosts checked at once
+	linkCheckHosts = 4
+	// linkCheckDelay is the pause between requests to the same host
+	linkCheckDelay = time.Second
+	// linkCheckTimeout is the longest a single request may take
+	linkCheckTimeout = 15 * time.Second
+	// linkCheckMaxRedirects is the longest internal redirect chain followed
+	linkCheckMaxRedirects = 5
+
+	linkCheckUserAgent = "Spotlight PA Almanack link checker (+https://www.spotlightpa.org)"
+)
+
+// syncPageLinks replaces the links saved for page with the links in it now.
+// Links that were already there keep their last check result.
+func (svc Services) syncPageLinks(ctx context.Context, txq *db.Queries, page *db.Page) (err error) {
+	defer errorx.Trace(&err)
+
+	links := page.Links()
+	arg := db.UpsertPageLinksParams{
+		PageID:   page.ID,
+		URLs:     make([]string, 0, len(links)),
+		Sources:  make([]string, 0, len(links)),
+		Internal: make([]bool, 0, len(links)),
+	}
+	for _, link := range links {
+		arg.URLs = append(arg.URLs, link.URL)
+		arg.Sources = append(arg.Sources, link.Source)
+		arg.Internal = append(arg.Internal, link.Internal)
+	}
+	if err = txq.DeletePageLinksExcept(ctx, db.DeletePageLinksExceptParams{
+		PageID: page.ID,
+		URLs:   arg.URLs,
+	}); err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return txq.UpsertPageLinks(ctx, arg)
+}
+
+// SyncPublishedPageLinks saves the links of every published page.
+// Pages normally sync their links when published,
+// so this is only needed to fill in pages published before link checking.
+func (svc Services) SyncPublishedPageLinks(ctx context.Context) (err error) {
+	defer errorx.Trace(&err)
+
+	ids, err := svc.Queries.ListPageIDsByFilter(ctx, db.ListPageIDsByFilterParams{
+		FilePath: "content/%",
+	})
+	if err != nil {
+		return err
+	}
+	return flowmatic.Each(flowmatic.MaxProcs, ids, func(id int64) error {
+		page, err := svc.Queries.GetPageByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !page.LastPublished.Valid {
+			return nil
+		}
+		return svc.syncPageLinks(ctx, svc.Queries, &page)
+	})
+}
+
+// CheckLinks checks up to limit links on published pages
+// that have not been checked recently.
+// Internal links are resolved against pages and redirects in the database,
+// allowing for the list pages that Hugo generates.
+// External links are requested a few hosts at a time,
+// pausing between requests to the same host.
+func (svc Services) CheckLinks(ctx context.Context, limit int) (err error) {
+	defer errorx.Trace(&err)
+
+	urls, err := svc.Queries.ListLinksToCheck(ctx, db.ListLinksToCheckParams{
+		CheckedBefore: time.Now().Add(-linkCheckMaxAge),
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		return err
+	}
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "Services.CheckLinks", "count", len(urls))
+
+	byHost := make(map[string][]string)
+	var errs []error
+	for _, link := range urls {
+		if strings.HasPrefix(link, "/") {
+			problem, err := svc.checkInternalLink(ctx, link)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			errs = append(errs, svc.Queries.UpdateLinkCheck(ctx, db.UpdateLinkCheckParams{
+				URL:    link,
+				Error:  problem,
+				Broken: problem != "",
+			}))
+			continue
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			// Should be impossible after NormalizeLink
+			errs = append(errs, err)
+			continue
+		}
+		byHost[u.Host] = append(byHost[u.Host], link)
+	}
+	hosts := slices.Sorted(maps.Keys(byHost))
+	errs = append(errs, flowmatic.Each(linkCheckHosts, hosts, func(host string) error {
+		var errs []error
+		for i, link := range byHost[host] {
+			if i > 0 && !timex.Sleep(ctx, linkCheckDelay) {
+				return errors.Join(append(errs, ctx.Err())...)
+			}
+			status, problem, broken := svc.checkExternalLink(ctx, link)
+			errs = append(errs, svc.Queries.UpdateLinkCheck(ctx, db.UpdateLinkCheckParams{
+				URL:    link,
+				Status: int32(status),
+				Error:  problem,
+				Broken: broken,
+			}))
+		}
+		return errors.Join(errs...)
+	}))
+	return errors.Join(errs...)
+}
+
+// checkInternalLink looks for a published page at upath,
+// following redirects within the site.
+// It returns a description of the problem if the link is broken.
+func (svc Services) checkInternalLink(ctx context.Context, upath string) (problem string, err error) {
+	defer errorx.Trace(&err)
+
+	for range linkCheckMaxRedirects {
+		page, err := svc.Queries.GetPageByURLPath(ctx, upath)
+		if err != nil && !db.IsNotFound(err) {
+			return "", err
+		}
+		if err == nil && page.LastPublished.Valid {
+			return "", nil
+		}
+		redirect, err := svc.Queries.GetRedirect(ctx, upath)
+		if db.IsNotFound(err) {
+			redirect, err = svc.Queries.GetRedirect(ctx, strings.TrimSuffix(upath, "/"))
+		}
+		if db.IsNotFound(err) {
+			generated, err := svc.isHugoListPage(ctx, upath)
+			if err != nil {
+				return "", err
+			}
+			if generated {
+				return "", nil
+			}
+			return "no published page or redirect", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		to, internal, ok := db.NormalizeLink(redirect.To)
+		if !ok {
+			return fmt.Sprintf("bad redirect to %q", redirect.To), nil
+		}
+		if !internal {
+			// Trust redirects off site
+			return "", nil
+		}
+		upath = to
+	}
+	return "too many redirects", nil
+}
+
+// Later pages of a Hugo list
+var paginationRe = lazy.RE(`/page/\d+/$`)
+
+// isHugoListPage reports whether Hugo builds a list page at upath
+// that has no page of its own in the database:
+// the home page, section and taxonomy lists, and their later pages.
+func (svc Services) isHugoListPage(ctx context.Context, upath string) (bool, error) {
+	upath = paginationRe().ReplaceAllString(upath, "/")
+	if upath == "/" {
+		return true, nil
+	}
+	parts := strings.Split(strings.Trim(upath, "/"), "/")
+	if slices.Contains(Taxonomies, parts[0]) && len(parts) <= 2 {
+		return true, nil
+	}
+	if len(parts) > 1 {
+		return false, nil
+	}
+	return svc.Queries.HasPublishedPagesInSection(ctx, parts[0])
+}
+
+// checkExternalLink requests link and returns the status code,
+// a description of any problem, and whether the link is broken.
+// Only missing pages, server errors, and failed requests count as broken;
+// sites that turn away bots with 403 or 429 are not reported.
+func (svc Services) checkExternalLink(ctx context.Context, link string) (status int, problem string, broken bool) {
+	ctx, cancel := context.WithTimeout(ctx, linkCheckTimeout)
+	defer cancel()
+
+	status, err := svc.fetchLinkStatus(ctx, http.MethodHead, link)
+	// Some servers don't support HEAD
+	if err != nil || status == http.StatusMethodNotAllowed ||
+		status == http.StatusNotImplemented || status == http.StatusForbidden {
+		status, err = svc.fetchLinkStatus(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		return 0, err.Error(), true
+	}
+	switch {
+	case status == http.StatusNotFound, status == http.StatusGone, status >= 500:
+		return status, http.StatusText(status), true
+	case status >= 400:
+		return status, http.StatusText(status), false
+	}
+	return status, "", false
+}
+
+func (svc Services) fetchLinkStatus(ctx context.Context, method, link string) (status int, err error) {
+	err = requests.
+		URL(link).
+		Client(cmp.Or(svc.Client, http.DefaultClient)).
+		Method(method).
+		UserAgent(linkCheckUserAgent).
+		AddValidator(func(res *http.Response) error {
+			status = res.StatusCode
+			return nil
+		}).
+		// Don't download whole pages for a GET
+		Handle(func(res *http.Response) error { return nil }).
+		Fetch(ctx)
+	return status, err
+}
+
+// BrokenLinkPage lists the broken links on one page.
+type BrokenLinkPage struct {
+	PageID   int64        `json:"page_id"`
+	FilePath string       `json:"file_path"`
+	URLPath  string       `json:"url_path"`
+	Internal []BrokenLink `json:"internal"`
+	External []BrokenLink `json:"external"`
+}
+
+// BrokenLink is a link that failed its last check.
+type BrokenLink struct {
+	URL       string    `json:"url"`
+	Source    string    `json:"source"`
+	Status    int32     `json:"status"`
+	Error     string    `json:"error"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ListBrokenLinks returns the broken links on published pages, by page.
+func (svc Services) ListBrokenLinks(ctx context.Context) (pages []BrokenLinkPage, err error) {
+	defer errorx.Trace(&err)
+
+	rows, err := svc.Queries.ListBrokenLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pages = []BrokenLinkPage{}
+	for _, row := range rows {
+		if len(pages) == 0 || pages[len(pages)-1].PageID != row.PageID {
+			pages = append(pages, BrokenLinkPage{
+				PageID:   row.PageID,
+				FilePath: row.FilePath,
+				URLPath:  row.URLPath.String,
+				Internal: []BrokenLink{},
+				External: []BrokenLink{},
+			})
+		}
+		page := &pages[len(pages)-1]
+		link := BrokenLink{
+			URL:       row.URL,
+			Source:    row.Source,
+			Status:    row.Status,
+			Error:     row.Error,
+			CheckedAt: row.CheckedAt.Time,
+		}
+		if row.Internal {
+			page.Internal = append(page.Internal, link)
+		} else {
+			page.External = append(page.External, link)
+		}
+	}
+	return pages, nil
+}
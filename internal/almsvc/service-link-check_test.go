@@ -0,0 +1,58 @@
+package almsvc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+)
+
+func TestCheckExternalLink(t *testing.T) {
+	almlog.UseTestLogger(t)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		be.Equal(t, linkCheckUserAgent, r.UserAgent())
+		switch r.URL.Path {
+		case "/ok":
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+		case "/bot-wall":
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/error":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	var svc Services
+	cases := []struct {
+		path   string
+		status int
+		broken bool
+	}{
+		{"/ok", 200, false},
+		{"/no-head", 200, false},
+		{"/redirect", 200, false},
+		{"/bot-wall", 429, false},
+		{"/error", 502, true},
+		{"/missing", 404, true},
+	}
+	for _, tc := range cases {
+		status, _, broken := svc.checkExternalLink(t.Context(), ts.URL+tc.path)
+		be.Equal(t, tc.status, status)
+		be.Equal(t, tc.broken, broken)
+	}
+
+	ts.Close()
+	status, problem, broken := svc.checkExternalLink(t.Context(), ts.URL+"/ok")
+	be.Equal(t, 0, status)
+	be.Nonzero(t, problem)
+	be.True(t, broken)
+}
@@ -0,0 +1,202 @@
+package almsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// PageFilter selects pages for a bulk edit. Blank fields match every page.
+type PageFilter struct {
+	// FilePath is a prefix, like content/news/
+	FilePath string `json:"file_path"`
+	Topic    string `json:"topic"`
+	// Author matches the authors list or part of the byline
+	Author          string    `json:"author"`
+	PublishedAfter  time.Time `json:"published_after,omitzero"`
+	PublishedBefore time.Time `json:"published_before,omitzero"`
+}
+
+// BulkEdit applies frontmatter operations to every page matching Filter.
+// Unless Apply is set, it is a dry run that only previews the changes.
+type BulkEdit struct {
+	Filter PageFilter         `json:"filter"`
+	Ops    []db.FrontmatterOp `json:"ops"`
+	Apply  bool               `json:"apply"`
+}
+
+// BulkEditChange previews the changes to one page.
+type BulkEditChange struct {
+	ID        int64                  `json:"id"`
+	FilePath  string                 `json:"file_path"`
+	Published bool                   `json:"published"`
+	Changes   []db.FrontmatterChange `json:"changes"`
+}
+
+// bulkEditBatchSize is the number of pages edited per transaction and commit.
+const bulkEditBatchSize = 25
+
+// CreateBulkEditJob checks edit and queues a job to run it.
+func (svc Services) CreateBulkEditJob(ctx context.Context, edit BulkEdit) (job db.Job, err error) {
+	defer errorx.Trace(&err)
+
+	var v resperr.Validator
+	v.AddIf("file_path", !strings.HasPrefix(edit.Filter.FilePath, "content/"),
+		"file path must start with content/")
+	v.AddIf("file_path", strings.ContainsAny(edit.Filter.FilePath, `%_\`),
+		"file path is a prefix, not a pattern")
+	v.AddIf("published_before",
+		!edit.Filter.PublishedBefore.IsZero() &&
+			edit.Filter.PublishedBefore.Before(edit.Filter.PublishedAfter),
+		"date range is backwards")
+	v.AddIf("ops", len(edit.Ops) == 0, "at least one operation is required")
+	for i, op := range edit.Ops {
+		if opErr := op.Validate(); opErr != nil {
+			v.Add("ops", "operation %d: %v", i+1, opErr)
+		}
+	}
+	if err = v.Err(); err != nil {
+		return job, err
+	}
+	return svc.CreateJob(ctx, JobPageBulkEdit, edit)
+}
+
+func (svc Services) runPageBulkEdit(ctx context.Context, jp *JobProgress) (err error) {
+	defer errorx.Trace(&err)
+
+	var edit BulkEdit
+	if err = jp.Params(&edit); err != nil {
+		return err
+	}
+	f := edit.Filter
+	ids, err := svc.Queries.ListPageIDsByFilter(ctx, db.ListPageIDsByFilterParams{
+		FilePath:        f.FilePath + "%",
+		Topic:           f.Topic,
+		Author:          f.Author,
+		PublishedAfter:  pgtype.Timestamptz{Time: f.PublishedAfter, Valid: !f.PublishedAfter.IsZero()},
+		PublishedBefore: pgtype.Timestamptz{Time: f.PublishedBefore, Valid: !f.PublishedBefore.IsZero()},
+	})
+	if err != nil {
+		return err
+	}
+	if err = jp.SetTotal(ctx, len(ids)); err != nil {
+		return err
+	}
+
+	changes := []BulkEditChange{}
+	published := 0
+	for batch := range slices.Chunk(ids, bulkEditBatchSize) {
+		batchChanges, n, warning, err := svc.bulkEditPages(ctx, batch, edit)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, batchChanges...)
+		published += n
+		if err = jp.Advance(ctx, len(batch), warning); err != nil {
+			return err
+		}
+	}
+	jp.SetResult(db.Map{
+		"apply":     edit.Apply,
+		"matched":   len(ids),
+		"changed":   len(changes),
+		"published": published,
+		"pages":     changes,
+	})
+	return nil
+}
+
+// bulkEditPages applies edit to the pages with ids
+// and republishes the published ones in a single commit.
+// Pages that fail a test op are skipped.
+// On a dry run, nothing is saved.
+func (svc Services) bulkEditPages(ctx context.Context, ids []int64, edit BulkEdit) (changes []BulkEditChange, n int, warning, err error) {
+	defer errorx.Trace(&err)
+
+	var (
+		published []db.Page
+		warnings  []error
+	)
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		changes, published, warnings = nil, nil, nil
+		files := make(map[string][]byte)
+		for _, id := range ids {
+			page, txerr := txq.GetPageByID(ctx, id)
+			if txerr != nil {
+				return txerr
+			}
+			fm, patchErr := page.Frontmatter.Patch(edit.Ops)
+			if errors.Is(patchErr, db.ErrPatchTest) {
+				continue
+			}
+			if patchErr != nil {
+				warnings = append(warnings, fmt.Errorf("%s: %w", page.FilePath, patchErr))
+				continue
+			}
+			diff := db.DiffFrontmatter(page.Frontmatter, fm)
+			if len(diff) == 0 {
+				continue
+			}
+			candidate := page
+			candidate.Frontmatter = fm
+			if page.LastPublished.Valid {
+				if invalid := candidate.Validate(); invalid != nil {
+					warnings = append(warnings, fmt.Errorf("%s: %w", page.FilePath, invalid))
+					continue
+				}
+			}
+			changes = append(changes, BulkEditChange{
+				ID:        page.ID,
+				FilePath:  page.FilePath,
+				Published: page.LastPublished.Valid,
+				Changes:   diff,
+			})
+			if !edit.Apply {
+				continue
+			}
+			page, txerr = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+				ID:             page.ID,
+				SetFrontmatter: true,
+				Frontmatter:    fm,
+				ScheduleFor:    db.NullTime,
+			})
+			if txerr != nil {
+				return txerr
+			}
+			if !page.LastPublished.Valid {
+				continue
+			}
+			// The saved url_path is still the old one,
+			// so a slug or URL change is aliased and redirected
+			var warning error
+			if page, txerr, warning = svc.stagePagePublish(ctx, txq, &page, files); txerr != nil {
+				return txerr
+			}
+			if warning != nil {
+				warnings = append(warnings, fmt.Errorf("%s: %w", page.FilePath, warning))
+			}
+			published = append(published, page)
+		}
+		msg := fmt.Sprintf("Content: bulk editing %d pages", len(published))
+		return svc.ContentStore.UpdateFiles(ctx, msg, files)
+	})
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	for _, page := range published {
+		_, indexErr := svc.Indexer.SaveObject(page.ToIndex(), ctx)
+		warnings = append(warnings, indexErr)
+	}
+	return changes, len(published), errors.Join(warnings...), nil
+}
@@ -0,0 +1,117 @@
+package almsvc
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+// CorrectionKinds are the kinds of page_correction, in the order editors pick from.
+var CorrectionKinds = []string{"correction", "clarification", "update"}
+
+const (
+	correctionsFeedPath = "feeds/corrections.json"
+	// correctionsFeedLimit is the number of corrections in the feed
+	correctionsFeedLimit = 500
+)
+
+// SavePageCorrection creates c, or updates it if it has an ID.
+// New corrections are credited to the current user
+// and dated now unless CorrectedAt is set.
+// Corrections only appear on the site after the page is republished.
+func (svc Services) SavePageCorrection(ctx context.Context, c db.PageCorrection) (saved db.PageCorrection, err error) {
+	defer errorx.Trace(&err)
+
+	c.Note = strings.TrimSpace(c.Note)
+	var v resperr.Validator
+	v.AddIf("kind", !slices.Contains(CorrectionKinds, c.Kind),
+		"kind must be one of %s", strings.Join(CorrectionKinds, ", "))
+	v.AddIf("note", c.Note == "", "note is required")
+	if err = v.Err(); err != nil {
+		return saved, err
+	}
+	if c.CorrectedAt.IsZero() {
+		c.CorrectedAt = time.Now()
+	}
+	if c.ID != 0 {
+		saved, err = svc.Queries.UpdatePageCorrection(ctx, db.UpdatePageCorrectionParams{
+			ID:          c.ID,
+			Kind:        c.Kind,
+			Note:        c.Note,
+			CorrectedAt: c.CorrectedAt,
+		})
+		return saved, db.NoRowsAs404(err, "could not find correction %d", c.ID)
+	}
+	return svc.Queries.CreatePageCorrection(ctx, db.CreatePageCorrectionParams{
+		PageID:      c.PageID,
+		Kind:        c.Kind,
+		Note:        c.Note,
+		CorrectedAt: c.CorrectedAt,
+		CreatedBy:   netlifyid.FromContext(ctx).Email(),
+	})
+}
+
+// setPageCorrections renders the corrections to page into its frontmatter
+// for Hugo, oldest first.
+func setPageCorrections(ctx context.Context, txq *db.Queries, page *db.Page) (err error) {
+	defer errorx.Trace(&err)
+
+	corrections, err := txq.ListPageCorrections(ctx, page.ID)
+	if err != nil {
+		return err
+	}
+	if page.Frontmatter == nil {
+		page.Frontmatter = db.Map{}
+	}
+	if len(corrections) == 0 {
+		delete(page.Frontmatter, "corrections")
+		return nil
+	}
+	page.Frontmatter["corrections"] = correctionsFrontmatter(corrections)
+	return nil
+}
+
+func correctionsFrontmatter(corrections []db.PageCorrection) []any {
+	// Frontmatter is stored as JSON, so use the types JSON decodes to
+	items := make([]any, 0, len(corrections))
+	for _, c := range corrections {
+		items = append(items, map[string]any{
+			"date": timex.ToEST(c.CorrectedAt).Format(time.RFC3339),
+			"kind": c.Kind,
+			"note": c.Note,
+		})
+	}
+	return items
+}
+
+// UpdateCorrectionsFeed uploads the site-wide list of published corrections,
+// newest first, for the public corrections page.
+func (svc Services) UpdateCorrectionsFeed(ctx context.Context) (err error) {
+	defer errorx.Trace(&err)
+
+	corrections, err := svc.Queries.ListPublishedCorrections(ctx, correctionsFeedLimit)
+	if err != nil {
+		return err
+	}
+	if corrections == nil {
+		corrections = []db.ListPublishedCorrectionsRow{}
+	}
+	return UploadJSON(
+		ctx,
+		svc.FileStore,
+		correctionsFeedPath,
+		"public, max-age=300",
+		struct {
+			Corrections []db.ListPublishedCorrectionsRow `json:"corrections"`
+		}{
+			corrections,
+		},
+	)
+}
@@ -0,0 +1,48 @@
+package almsvc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestCorrectionsFrontmatter(t *testing.T) {
+	page := db.Page{
+		Frontmatter: db.Map{
+			"title": "Budget passes",
+			"corrections": correctionsFrontmatter([]db.PageCorrection{{
+				Kind:        "correction",
+				Note:        `An earlier version misstated the "total".`,
+				CorrectedAt: time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC),
+				CreatedBy:   "editor@spotlightpa.org",
+			}, {
+				Kind:        "update",
+				Note:        "The governor signed the bill.",
+				CorrectedAt: time.Date(2025, 7, 1, 16, 30, 0, 0, time.UTC),
+			}}),
+		},
+		Body: "Lorem ipsum.",
+	}
+	toml, err := page.ToTOML()
+	be.NilErr(t, err)
+	be.Equal(t, strings.TrimLeft(`
++++
+title = "Budget passes"
+
+[[corrections]]
+  date = "2025-03-04T10:00:00-05:00"
+  kind = "correction"
+  note = "An earlier version misstated the \"total\"."
+
+[[corrections]]
+  date = "2025-07-01T12:30:00-04:00"
+  kind = "update"
+  note = "The governor signed the bill."
++++
+
+Lorem ipsum.
+`, "\n"), toml)
+}
@@ -0,0 +1,147 @@
+package almsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+)
+
+// PopExpiredPages unpublishes pages whose expire_at has passed.
+// Expired pages are removed from the homepage and sidebar, from the site, and from search.
+func (svc Services) PopExpiredPages(ctx context.Context) (err, warning error) {
+	defer errorx.Trace(&err)
+
+	var expired []db.Page
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		expired, txerr = txq.PopExpiredPages(ctx)
+		if txerr != nil || len(expired) == 0 {
+			return txerr
+		}
+		paths := make([]string, 0, len(expired))
+		for _, page := range expired {
+			if txerr = txq.CreatePageRevision(ctx, db.CreatePageRevisionParams{
+				PageID:    page.ID,
+				CreatedBy: netlifyid.FromContext(ctx).Email(),
+			}); txerr != nil {
+				return txerr
+			}
+			paths = append(paths, page.FilePath)
+		}
+		// Take the pages off the homepage and sidebar before removing them
+		for _, loc := range []string{HomepageLoc, SidebarLoc} {
+			if txerr = svc.dropSiteDataPages(ctx, txq, loc, paths); txerr != nil {
+				return txerr
+			}
+		}
+		for _, page := range expired {
+			msg := fmt.Sprintf("Content: expiring %q", pageTitle(&page))
+			if txerr = svc.ContentStore.DeleteFile(ctx, msg, page.FilePath); txerr != nil {
+				return txerr
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err, nil
+	}
+
+	var warnings []error
+	for _, page := range expired {
+		almlog.FromContext(ctx).InfoContext(ctx, "Services.PopExpiredPages: expired",
+			"id", page.ID,
+			"path", page.FilePath,
+		)
+		_, indexErr := svc.Indexer.DeleteObject(page.FullURL(), ctx)
+		warnings = append(warnings, indexErr)
+		warnings = append(warnings, svc.NotifyUnpublished(ctx, &page, ""))
+	}
+	return nil, errors.Join(warnings...)
+}
+
+// dropSiteDataPages removes references to the pages at filePaths
+// from the current and scheduled site data for loc.
+// If the current site data changes, it is republished.
+func (svc Services) dropSiteDataPages(ctx context.Context, txq *db.Queries, loc string, filePaths []string) (err error) {
+	defer errorx.Trace(&err)
+
+	if err = txq.LockSiteData(ctx, loc); err != nil {
+		return err
+	}
+	configs, err := txq.GetSiteData(ctx, loc)
+	if err != nil {
+		return err
+	}
+	for _, config := range configs {
+		data, changed := dropPageRefs(config.Data, filePaths)
+		if !changed {
+			continue
+		}
+		config.Data = data.(db.Map)
+		if err = txq.UpsertSiteData(ctx, db.UpsertSiteDataParams{
+			Key:         loc,
+			Data:        config.Data,
+			ScheduleFor: config.ScheduleFor,
+		}); err != nil {
+			return err
+		}
+		if config.PublishedAt.Valid {
+			if err = svc.PublishSiteConfig(ctx, &config); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropPageRefs returns a copy of v without any list items that refer to filePaths.
+// Homepage lists refer to pages by file path,
+// and sidebar items refer to them with a "page" key.
+func dropPageRefs(v any, filePaths []string) (any, bool) {
+	isRef := func(item any) bool {
+		switch item := item.(type) {
+		case string:
+			return slices.Contains(filePaths, item)
+		case map[string]any:
+			path, _ := item["page"].(string)
+			return slices.Contains(filePaths, path)
+		}
+		return false
+	}
+	switch v := v.(type) {
+	case db.Map:
+		m, changed := dropPageRefs(map[string]any(v), filePaths)
+		return db.Map(m.(map[string]any)), changed
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		changed := false
+		for key, val := range v {
+			var c bool
+			m[key], c = dropPageRefs(val, filePaths)
+			changed = changed || c
+		}
+		return m, changed
+	case []any:
+		s := make([]any, 0, len(v))
+		changed := false
+		for _, item := range v {
+			if isRef(item) {
+				changed = true
+				continue
+			}
+			item, c := dropPageRefs(item, filePaths)
+			changed = changed || c
+			s = append(s, item)
+		}
+		return s, changed
+	}
+	return v, false
+}
@@ -0,0 +1,55 @@
+package almsvc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestDropPageRefs(t *testing.T) {
+	cases := map[string]struct {
+		in, want string
+		changed  bool
+	}{
+		"empty": {
+			`{}`, `{}`, false,
+		},
+		"homepage": {
+			`{"featuredStories":["content/news/a.md","content/news/b.md"],"topSlots":["content/news/b.md"]}`,
+			`{"featuredStories":["content/news/a.md"],"topSlots":[]}`,
+			true,
+		},
+		"homepage-unchanged": {
+			`{"featuredStories":["content/news/a.md"],"subfeatures":[]}`,
+			`{"featuredStories":["content/news/a.md"],"subfeatures":[]}`,
+			false,
+		},
+		"sidebar": {
+			`{"items":[{"label":"Pick","page":"content/news/b.md"},{"label":"Other","page":"content/news/c.md"}]}`,
+			`{"items":[{"label":"Other","page":"content/news/c.md"}]}`,
+			true,
+		},
+		"not-a-list-item": {
+			`{"page":"content/news/b.md"}`,
+			`{"page":"content/news/b.md"}`,
+			false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var in db.Map
+			be.NilErr(t, json.Unmarshal([]byte(tc.in), &in))
+			before, _ := json.Marshal(in)
+			got, changed := dropPageRefs(in, []string{"content/news/b.md"})
+			be.Equal(t, tc.changed, changed)
+			b, err := json.Marshal(got)
+			be.NilErr(t, err)
+			be.Equal(t, tc.want, string(b))
+			// The input is not modified
+			after, _ := json.Marshal(in)
+			be.Equal(t, string(before), string(after))
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package almsvc
+
+import (
+	"context"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+)
+
+// PageLockTTL is how long an editor counts as present after their last heartbeat.
+// The page editor sends a heartbeat about once a minute.
+const PageLockTTL = 2 * time.Minute
+
+// HeartbeatPageLock records that the current user is editing a page
+// and returns everyone else who is editing it.
+// Users without an email address, such as in local development, are not recorded.
+func (svc Services) HeartbeatPageLock(ctx context.Context, pageID int64) (editors []db.PageLock, err error) {
+	defer errorx.Trace(&err)
+
+	user := netlifyid.FromContext(ctx)
+	if user.Email() != "" {
+		if _, err = svc.Queries.UpsertPageLock(ctx, db.UpsertPageLockParams{
+			PageID: pageID,
+			Email:  user.Email(),
+			Name:   user.Username(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return svc.ListPageEditors(ctx, pageID)
+}
+
+// ListPageEditors returns the users other than the current user
+// with a fresh lock on a page.
+func (svc Services) ListPageEditors(ctx context.Context, pageID int64) (editors []db.PageLock, err error) {
+	defer errorx.Trace(&err)
+
+	editors, err = svc.Queries.ListPageLocks(ctx, db.ListPageLocksParams{
+		PageID:      pageID,
+		ExceptEmail: netlifyid.FromContext(ctx).Email(),
+		Since:       time.Now().Add(-PageLockTTL),
+	})
+	if editors == nil {
+		editors = []db.PageLock{}
+	}
+	return
+}
+
+// ReleasePageLock removes the current user's lock on a page.
+func (svc Services) ReleasePageLock(ctx context.Context, pageID int64) (err error) {
+	defer errorx.Trace(&err)
+
+	return svc.Queries.DeletePageLock(ctx, db.DeletePageLockParams{
+		PageID: pageID,
+		Email:  netlifyid.FromContext(ctx).Email(),
+	})
+}
+
+// CleanPageLocks removes locks without a fresh heartbeat.
+func (svc Services) CleanPageLocks(ctx context.Context) (err error) {
+	defer errorx.Trace(&err)
+
+	n, err := svc.Queries.DeleteStalePageLocks(ctx, time.Now().Add(-PageLockTTL))
+	if err != nil {
+		return err
+	}
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "Services.CleanPageLocks", "deleted", n)
+	return nil
+}
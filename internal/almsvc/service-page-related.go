@@ -0,0 +1,170 @@
+package almsvc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+const (
+	// relatedPagesLimit is the number of suggestions kept per page
+	relatedPagesLimit = 10
+	// relatedPagesRecent is how far back the nightly job looks for pages
+	relatedPagesRecent = 30 * 24 * time.Hour
+	// relatedPagesHour is the hour, Eastern time, when the nightly job is queued
+	relatedPagesHour = 2
+)
+
+// relatedPagesSections are the content sections that get suggestions.
+var relatedPagesSections = []string{
+	"content/news/",
+	"content/statecollege/",
+	"content/berks/",
+}
+
+// RelatedPage is a suggestion for a page's related list.
+type RelatedPage struct {
+	ID       int64  `json:"id"`
+	FilePath string `json:"file_path"`
+	// ContentPath is FilePath relative to the content directory, e.g. news/x.md.
+	// This is what goes in the related frontmatter list,
+	// because the theme looks up each entry with .Site.GetPage.
+	ContentPath     string    `json:"content_path"`
+	URLPath         string    `json:"url_path"`
+	Title           string    `json:"title"`
+	PublicationDate time.Time `json:"publication_date,omitzero"`
+	Score           float64   `json:"score"`
+}
+
+func newRelatedPage(id int64, filePath string, urlPath pgtype.Text, title string, pubDate pgtype.Timestamptz, score float64) RelatedPage {
+	return RelatedPage{
+		ID:              id,
+		FilePath:        filePath,
+		ContentPath:     strings.TrimPrefix(filePath, "content/"),
+		URLPath:         urlPath.String,
+		Title:           title,
+		PublicationDate: pubDate.Time,
+		Score:           score,
+	}
+}
+
+// ListRelatedPages returns suggestions for the related list of the page with id,
+// along with when they were computed.
+// Suggestions saved by the nightly job are used unless refresh is set
+// or there are none.
+func (svc Services) ListRelatedPages(ctx context.Context, id int64, refresh bool) (pages []RelatedPage, computedAt time.Time, err error) {
+	defer errorx.Trace(&err)
+
+	if !refresh {
+		rows, err := svc.Queries.ListRelatedPages(ctx, id)
+		if err != nil {
+			return nil, computedAt, err
+		}
+		if len(rows) > 0 {
+			pages = make([]RelatedPage, 0, len(rows))
+			for _, row := range rows {
+				pages = append(pages, newRelatedPage(
+					row.ID, row.FilePath, row.URLPath, row.Title, row.PublicationDate, row.Score,
+				))
+				computedAt = row.CreatedAt
+			}
+			return pages, computedAt, nil
+		}
+	}
+	pages, err = svc.saveRelatedPages(ctx, id)
+	return pages, time.Now(), err
+}
+
+// saveRelatedPages ranks suggestions for the page with id and saves them.
+func (svc Services) saveRelatedPages(ctx context.Context, id int64) (pages []RelatedPage, err error) {
+	defer errorx.Trace(&err)
+
+	rows, err := svc.Queries.ListRelatedPageSuggestions(ctx, db.ListRelatedPageSuggestionsParams{
+		ID:    id,
+		Limit: relatedPagesLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	pages = make([]RelatedPage, 0, len(rows))
+	arg := db.CreateRelatedPagesParams{
+		PageID:     id,
+		RelatedIds: make([]int64, 0, len(rows)),
+		Scores:     make([]float64, 0, len(rows)),
+	}
+	for _, row := range rows {
+		pages = append(pages, newRelatedPage(
+			row.ID, row.FilePath, row.URLPath, row.Title, row.PublicationDate, row.Score,
+		))
+		arg.RelatedIds = append(arg.RelatedIds, row.ID)
+		arg.Scores = append(arg.Scores, row.Score)
+	}
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) error {
+		if err := txq.DeleteRelatedPages(ctx, id); err != nil {
+			return err
+		}
+		return txq.CreateRelatedPages(ctx, arg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// QueueRelatedPagesJob queues the job that precomputes related page suggestions
+// once a night.
+func (svc Services) QueueRelatedPagesJob(ctx context.Context) (err error) {
+	defer errorx.Trace(&err)
+
+	now := time.Now()
+	if timex.ToEST(now).Hour() != relatedPagesHour {
+		return nil
+	}
+	_, err = svc.Queries.CreateJobUnlessRecent(ctx, db.CreateJobUnlessRecentParams{
+		Kind:         JobPageRelated,
+		CreatedSince: now.Add(-12 * time.Hour),
+	})
+	if db.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func (svc Services) runPageRelated(ctx context.Context, jp *JobProgress) (err error) {
+	defer errorx.Trace(&err)
+
+	since := pgtype.Timestamptz{
+		Time:  time.Now().Add(-relatedPagesRecent),
+		Valid: true,
+	}
+	var ids []int64
+	for _, section := range relatedPagesSections {
+		sectionIDs, err := svc.Queries.ListPageIDsByFilter(ctx, db.ListPageIDsByFilterParams{
+			FilePath:       section + "%",
+			PublishedAfter: since,
+		})
+		if err != nil {
+			return err
+		}
+		ids = append(ids, sectionIDs...)
+	}
+	if err = jp.SetTotal(ctx, len(ids)); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err = svc.saveRelatedPages(ctx, id); err != nil {
+			return err
+		}
+		if err = jp.Advance(ctx, 1); err != nil {
+			return err
+		}
+	}
+	jp.SetResult(db.Map{"pages": len(ids)})
+	return nil
+}
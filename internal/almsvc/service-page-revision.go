@@ -0,0 +1,42 @@
+package almsvc
+
+import (
+	"context"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// RestorePageRevision copies the frontmatter and body of a revision back onto its page.
+// If the page is published or due to be, the restored page is republished.
+func (svc Services) RestorePageRevision(ctx context.Context, revisionID int64) (page *db.Page, err, warning error) {
+	defer errorx.Trace(&err)
+
+	rev, err := svc.Queries.GetPageRevisionByID(ctx, revisionID)
+	if err != nil {
+		err = db.NoRowsAs404(err, "could not find page revision %d", revisionID)
+		return
+	}
+
+	var restored db.Page
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		restored, txerr = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+			ID:             rev.PageID,
+			SetFrontmatter: true,
+			Frontmatter:    rev.Frontmatter,
+			SetBody:        true,
+			Body:           rev.Body,
+			ScheduleFor:    db.NullTime,
+		})
+		if txerr != nil || !restored.ShouldPublish() {
+			return txerr
+		}
+		txerr, warning = svc.PublishPage(ctx, txq, &restored)
+		return txerr
+	})
+	if err != nil {
+		return
+	}
+	return &restored, nil, warning
+}
@@ -0,0 +1,165 @@
+package almsvc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// PageStates are the values a page search can filter state by.
+var PageStates = []string{"published", "scheduled", "draft"}
+
+const (
+	pageSearchLimit = 50
+	// pageFacetLimit is the most values returned for each facet
+	pageFacetLimit = 25
+)
+
+// PageSearch filters a page search. Zero fields are ignored.
+type PageSearch struct {
+	Query           string
+	Section         string
+	Author          string
+	Topic           string
+	Series          string
+	State           string
+	SourceType      string
+	PublishedAfter  time.Time
+	PublishedBefore time.Time
+	// Cursor is the NextCursor of the previous result
+	Cursor string
+}
+
+type PageSearchResult struct {
+	Pages      []db.SearchPagesRow    `json:"pages"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+	Facets     map[string][]PageFacet `json:"facets,omitempty"`
+}
+
+type PageFacet struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// SearchPages returns a page of results for the search, newest first.
+// Facet counts are only returned with the first page of results,
+// since they don't change with the cursor.
+func (svc Services) SearchPages(ctx context.Context, s PageSearch) (res PageSearchResult, err error) {
+	defer errorx.Trace(&err)
+
+	s.Query = strings.TrimSpace(s.Query)
+	var v resperr.Validator
+	v.AddIf("section", s.Section != "" && !strings.HasPrefix(s.Section, "content/"),
+		"section must start with content/")
+	v.AddIf("section", strings.ContainsAny(s.Section, `%_\`),
+		"section may not contain wildcards")
+	v.AddIf("state", s.State != "" && !slices.Contains(PageStates, s.State),
+		"state must be one of %s", strings.Join(PageStates, ", "))
+	v.AddIf("published_before",
+		!s.PublishedAfter.IsZero() && !s.PublishedBefore.IsZero() &&
+			!s.PublishedBefore.After(s.PublishedAfter),
+		"published_before must be after published_after")
+	cursorDate, cursorID, cursorErr := decodePageCursor(s.Cursor)
+	v.AddIf("cursor", cursorErr != nil, "cursor is invalid")
+	if err = v.Err(); err != nil {
+		return res, err
+	}
+
+	after := timestamptzOrNull(s.PublishedAfter)
+	before := timestamptzOrNull(s.PublishedBefore)
+	pages, err := svc.Queries.SearchPages(ctx, db.SearchPagesParams{
+		Query:           s.Query,
+		Section:         s.Section,
+		Author:          s.Author,
+		Topic:           s.Topic,
+		Series:          s.Series,
+		State:           s.State,
+		SourceType:      s.SourceType,
+		PublishedAfter:  after,
+		PublishedBefore: before,
+		CursorDate:      timestamptzOrNull(cursorDate),
+		CursorID:        cursorID,
+		Limit:           pageSearchLimit + 1,
+	})
+	if err != nil {
+		return res, err
+	}
+	if len(pages) > pageSearchLimit {
+		pages = pages[:pageSearchLimit]
+		last := pages[len(pages)-1]
+		res.NextCursor = encodePageCursor(last.SortDate, last.ID)
+	}
+	if pages == nil {
+		pages = []db.SearchPagesRow{}
+	}
+	res.Pages = pages
+
+	if s.Cursor != "" {
+		return res, nil
+	}
+	facets, err := svc.Queries.SearchPageFacets(ctx, db.SearchPageFacetsParams{
+		Query:           s.Query,
+		PublishedAfter:  after,
+		PublishedBefore: before,
+		Section:         s.Section,
+		Author:          s.Author,
+		Topic:           s.Topic,
+		Series:          s.Series,
+		State:           s.State,
+		SourceType:      s.SourceType,
+		FacetLimit:      pageFacetLimit,
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Facets = make(map[string][]PageFacet)
+	for _, f := range facets {
+		res.Facets[f.Facet] = append(res.Facets[f.Facet], PageFacet{
+			Value: f.Value,
+			Count: f.Count,
+		})
+	}
+	return res, nil
+}
+
+func timestamptzOrNull(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero()}
+}
+
+// encodePageCursor makes an opaque cursor for the position after a search result.
+func encodePageCursor(sortDate time.Time, id int64) string {
+	s := fmt.Sprintf("%d.%d", sortDate.UnixMicro(), id)
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+func decodePageCursor(cursor string) (sortDate time.Time, id int64, err error) {
+	if cursor == "" {
+		return
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return
+	}
+	micro, idStr, ok := strings.Cut(string(b), ".")
+	if !ok {
+		err = fmt.Errorf("bad cursor: %q", b)
+		return
+	}
+	usec, err := strconv.ParseInt(micro, 10, 64)
+	if err != nil {
+		return
+	}
+	if id, err = strconv.ParseInt(idStr, 10, 64); err != nil {
+		return
+	}
+	return time.UnixMicro(usec), id, nil
+}
@@ -0,0 +1,27 @@
+package almsvc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+)
+
+func TestPageCursor(t *testing.T) {
+	date := time.Date(2025, 3, 4, 15, 0, 0, 123456000, time.UTC)
+	cursor := encodePageCursor(date, 42)
+	gotDate, gotID, err := decodePageCursor(cursor)
+	be.NilErr(t, err)
+	be.True(t, date.Equal(gotDate))
+	be.Equal(t, 42, gotID)
+
+	gotDate, gotID, err = decodePageCursor("")
+	be.NilErr(t, err)
+	be.True(t, gotDate.IsZero())
+	be.Equal(t, 0, gotID)
+
+	for _, bad := range []string{"!!", "MTIz", "YS5i"} {
+		_, _, err = decodePageCursor(bad)
+		be.Nonzero(t, err)
+	}
+}
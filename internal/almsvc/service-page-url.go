@@ -0,0 +1,63 @@
+package almsvc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+// setPageURLPath sets the url_path of a page about to be published from its frontmatter.
+// If the path has moved away from oldURLPath, the old path is added to the page's aliases
+// and redirected to the new path.
+// The warning reports any other pages that already use the new path.
+// The caller is responsible for saving the page.
+func setPageURLPath(ctx context.Context, txq *db.Queries, page *db.Page, oldURLPath string) (err, warning error) {
+	defer errorx.Trace(&err)
+
+	newURLPath := page.CalcURLPath()
+	if newURLPath == "" {
+		page.URLPath.String = oldURLPath
+		page.URLPath.Valid = oldURLPath != ""
+		return nil, nil
+	}
+	page.URLPath.String = newURLPath
+	page.URLPath.Valid = true
+	if oldURLPath == "" || strings.EqualFold(oldURLPath, newURLPath) {
+		return nil, nil
+	}
+
+	conflicts, err := txq.ListOtherPagesByURLPath(ctx, db.ListOtherPagesByURLPathParams{
+		URLPath: newURLPath,
+		ID:      page.ID,
+	})
+	if err != nil {
+		return err, nil
+	}
+	if len(conflicts) > 0 {
+		paths := make([]string, 0, len(conflicts))
+		for _, conflict := range conflicts {
+			paths = append(paths, conflict.FilePath)
+		}
+		warning = fmt.Errorf("URL %s is already used by %s",
+			newURLPath, strings.Join(paths, ", "))
+	}
+	page.SetAliases(oldURLPath, newURLPath)
+
+	if _, err = txq.UpsertRedirect(ctx, db.UpsertRedirectParams{
+		From:  oldURLPath,
+		To:    newURLPath,
+		Code:  http.StatusMovedPermanently,
+		Roles: []string{},
+	}); err != nil {
+		return err, warning
+	}
+	// Don't leave a redirect loop if a page moves back to an old URL
+	if err = txq.DeleteRedirect(ctx, newURLPath); err != nil {
+		return err, warning
+	}
+	return nil, warning
+}
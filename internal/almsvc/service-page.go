@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/spotlightpa/almanack/internal/almlog"
 	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
 	"github.com/spotlightpa/almanack/internal/utils/stringx"
 	"github.com/spotlightpa/almanack/internal/utils/timex"
 )
@@ -25,70 +27,184 @@ func (svc Services) PublishPage(ctx context.Context, txq *db.Queries, page *db.P
 	defer errorx.Trace(&err)
 
 	page.SetURLPath()
-	data, err := page.ToTOML()
+	// Stage the update while holding a lock.
+	// Then in one goroutine, do the GitHub publish.
+	// If it publishes, the caller commits the locked update. If not, rollback.
+	// In the background, index the staged page and issue a warning if it fails.
+	// Staging first means the indexer never reads the frontmatter
+	// while aliases and corrections are being rendered into it.
+	// If all this goes well, swap in the db.Page to the pointer
+	files := make(map[string][]byte)
+	p2, err, urlWarning := svc.stagePagePublish(ctx, txq, page, files)
 	if err != nil {
 		return
 	}
-	// Start two goroutines.
-	// In one, try the update while holding a lock.
-	// If the update succeeds, also do the GitHub publish.
-	// If it publishes, commit the locked update. If not, rollback.
-	// In the background, do the index and issue a warning if it fails.
-	// If all this goes well, swap in the db.Page to the pointer
-	var p2 db.Page
+	var idxWarning error
+	msg := fmt.Sprintf("Content: publishing %q", pageTitle(&p2))
 	err = flowmatic.Do(
 		func() (txerr error) {
 			defer errorx.Trace(&txerr)
 
-			p2, txerr = txq.UpdatePage(ctx, db.UpdatePageParams{
-				ID:               page.ID,
-				URLPath:          page.URLPath.String,
-				SetLastPublished: true,
-				SetFrontmatter:   false,
-				SetBody:          false,
-				SetScheduleFor:   false,
-				ScheduleFor:      db.NullTime,
-			})
-			if txerr != nil {
-				return txerr
-			}
-
-			if txerr = svc.EnsureTaxonomyPages(ctx, txq, &p2); txerr != nil {
-				return txerr
-			}
-
-			internalID, _ := page.Frontmatter["internal-id"].(string)
-			title := cmp.Or(internalID, page.FilePath)
-			msg := fmt.Sprintf("Content: publishing %q", title)
-			return svc.ContentStore.UpdateFile(ctx, msg, page.FilePath, []byte(data))
+			return svc.ContentStore.UpdateFiles(ctx, msg, files)
 		},
 		func() error {
-			_, warning = svc.Indexer.SaveObject(page.ToIndex(), ctx)
+			_, idxWarning = svc.Indexer.SaveObject(p2.ToIndex(), ctx)
 			return nil
 		})
 	if err != nil {
 		return
 	}
 	*page = p2
-	return
+	return nil, errors.Join(urlWarning, idxWarning)
 }
 
-func (svc Services) PublishJSONPage(ctx context.Context, txq *db.Queries, update db.UpdatePageParams) (page *db.Page, err error) {
+// stagePagePublish marks page as published in the database
+// and adds its content file, plus the files of any taxonomy pages it needs,
+// to files so that they can be committed together.
+// If the page has moved from the URL saved for it, the old URL is aliased and redirected.
+// The warning reports any other pages that already use the new URL.
+func (svc Services) stagePagePublish(ctx context.Context, txq *db.Queries, page *db.Page, files map[string][]byte) (p2 db.Page, err, warning error) {
 	defer errorx.Trace(&err)
 
-	// This will rollback on error
-	updatedPage, err := txq.UpdatePage(ctx, update)
+	if err, warning = setPageURLPath(ctx, txq, page, page.URLPath.String); err != nil {
+		return
+	}
+	if err = setPageCorrections(ctx, txq, page); err != nil {
+		return
+	}
+	data, err := page.ToTOML()
 	if err != nil {
 		return
 	}
 
-	data, err := updatedPage.ToJSON()
+	// Save the frontmatter too, since aliases and corrections were rendered into it
+	p2, err = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+		ID:               page.ID,
+		URLPath:          page.URLPath.String,
+		SetLastPublished: true,
+		SetFrontmatter:   true,
+		Frontmatter:      page.Frontmatter,
+		SetBody:          false,
+		SetScheduleFor:   false,
+		ScheduleFor:      db.NullTime,
+	})
+	if err != nil {
+		return
+	}
+
+	if err = svc.EnsureTaxonomyPages(ctx, txq, &p2, files); err != nil {
+		return
+	}
+	if err = svc.ensureAuthorPages(ctx, txq, &p2, files); err != nil {
+		return
+	}
+	if err = svc.syncPageLinks(ctx, txq, &p2); err != nil {
+		return
+	}
+	files[page.FilePath] = []byte(data)
+	return p2, nil, warning
+}
+
+func pageTitle(page *db.Page) string {
+	internalID, _ := page.Frontmatter["internal-id"].(string)
+	return cmp.Or(internalID, page.FilePath)
+}
+
+// UnpublishPage takes down a published or scheduled page.
+// The file is removed from the content store and the search index,
+// but the database row and its revisions are kept.
+// If redirectTo is set, the old URL path is redirected there.
+func (svc Services) UnpublishPage(ctx context.Context, id int64, redirectTo string) (page *db.Page, err, warning error) {
+	defer errorx.Trace(&err)
+
+	oldPage, err := svc.Queries.GetPageByID(ctx, id)
+	if err != nil {
+		err = db.NoRowsAs404(err, "could not find page %d", id)
+		return
+	}
+	if !oldPage.LastPublished.Valid && !oldPage.ScheduleFor.Valid {
+		err = resperr.New(http.StatusConflict,
+			"page %d is neither published nor scheduled", id)
+		return
+	}
+
+	var p2 db.Page
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		p2, txerr = txq.UnpublishPage(ctx, id)
+		if txerr != nil {
+			return txerr
+		}
+		if txerr = txq.CreatePageRevision(ctx, db.CreatePageRevisionParams{
+			PageID:    p2.ID,
+			CreatedBy: netlifyid.FromContext(ctx).Email(),
+		}); txerr != nil {
+			return txerr
+		}
+		if redirectTo != "" && oldPage.URLPath.String != "" {
+			if _, txerr = txq.UpsertRedirect(ctx, db.UpsertRedirectParams{
+				From:  oldPage.URLPath.String,
+				To:    redirectTo,
+				Code:  http.StatusMovedPermanently,
+				Roles: []string{},
+			}); txerr != nil {
+				return txerr
+			}
+		}
+		if !oldPage.LastPublished.Valid {
+			return nil
+		}
+		msg := fmt.Sprintf("Content: unpublishing %q", pageTitle(&oldPage))
+		return svc.ContentStore.DeleteFile(ctx, msg, oldPage.FilePath)
+	})
+	if err != nil {
+		return
+	}
+
+	var warnings []error
+	if oldPage.LastPublished.Valid {
+		_, indexErr := svc.Indexer.DeleteObject(oldPage.FullURL(), ctx)
+		warnings = append(warnings, indexErr)
+	}
+	warnings = append(warnings, svc.NotifyUnpublished(ctx, &oldPage, redirectTo))
+	return &p2, nil, errors.Join(warnings...)
+}
+
+func (svc Services) PublishJSONPage(ctx context.Context, txq *db.Queries, update db.UpdatePageParams) (page *db.Page, err, warning error) {
+	defer errorx.Trace(&err)
+
+	oldPage, err := txq.GetPageByID(ctx, update.ID)
+	if err != nil {
+		return
+	}
+	// This will rollback on error
+	updatedPage, err := txq.UpdatePageWithRevision(ctx, update)
 	if err != nil {
 		return
 	}
 
 	if !update.SetLastPublished {
-		return &updatedPage, nil
+		return &updatedPage, nil, nil
+	}
+
+	if err, warning = setPageURLPath(ctx, txq, &updatedPage, oldPage.URLPath.String); err != nil {
+		return
+	}
+	updatedPage, err = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+		ID:             updatedPage.ID,
+		SetFrontmatter: true,
+		Frontmatter:    updatedPage.Frontmatter,
+		URLPath:        updatedPage.URLPath.String,
+		ScheduleFor:    db.NullTime,
+	})
+	if err != nil {
+		return
+	}
+
+	data, err := updatedPage.ToJSON()
+	if err != nil {
+		return
 	}
 
 	msg := fmt.Sprintf("Content: publishing %q", updatedPage.FilePath)
@@ -96,7 +212,7 @@ func (svc Services) PublishJSONPage(ctx context.Context, txq *db.Queries, update
 		return
 	}
 
-	return &updatedPage, nil
+	return &updatedPage, nil, warning
 }
 
 func (svc Services) RefreshPageFromContentStore(ctx context.Context, page *db.Page) (err error) {
@@ -116,7 +232,10 @@ func (svc Services) RefreshPageFromContentStore(ctx context.Context, page *db.Pa
 }
 
 func (svc Services) PopScheduledPages(ctx context.Context) (err, warning error) {
-	var warnings []error
+	var (
+		published []db.Page
+		warnings  []error
+	)
 	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
 		defer errorx.Trace(&txerr)
 
@@ -124,35 +243,77 @@ func (svc Services) PopScheduledPages(ctx context.Context) (err, warning error)
 		if txerr != nil {
 			return
 		}
-		var errs []error
+		if len(pages) == 0 {
+			return nil
+		}
+		// Publish all the popped pages in a single commit
+		files := make(map[string][]byte)
+		titles := make([]string, 0, len(pages))
 		for _, page := range pages {
-			txerr, warning = svc.PublishPage(ctx, txq, &page)
-			errs = append(errs, txerr)
-			warnings = append(warnings, warning)
+			// Return invalid pages to draft rather than retrying them every run
+			if invalid := page.Validate(); invalid != nil {
+				if _, txerr = txq.UnpublishPage(ctx, page.ID); txerr != nil {
+					return txerr
+				}
+				if txerr = txq.CreatePageRevision(ctx, db.CreatePageRevisionParams{
+					PageID: page.ID,
+				}); txerr != nil {
+					return txerr
+				}
+				warnings = append(warnings, fmt.Errorf(
+					"could not publish scheduled page %q: %w", page.FilePath, invalid))
+				continue
+			}
+			var warning error
+			if _, txerr, warning = svc.stagePagePublish(ctx, txq, &page, files); txerr != nil {
+				return txerr
+			}
+			if warning != nil {
+				warnings = append(warnings, fmt.Errorf("%s: %w", page.FilePath, warning))
+			}
+			titles = append(titles, pageTitle(&page))
+			published = append(published, page)
 		}
-		return errors.Join(errs...)
+		if len(titles) == 0 {
+			return nil
+		}
+		msg := fmt.Sprintf("Content: publishing %q", titles[0])
+		if len(titles) > 1 {
+			msg = fmt.Sprintf("Content: publishing %d scheduled pages\n\n- %s",
+				len(titles), strings.Join(titles, "\n- "))
+		}
+		return svc.ContentStore.UpdateFiles(ctx, msg, files)
 	})
-	return err, errors.Join(warnings...)
+	if err != nil {
+		return err, nil
+	}
+	for _, page := range published {
+		_, warning = svc.Indexer.SaveObject(page.ToIndex(), ctx)
+		warnings = append(warnings, warning)
+	}
+	return nil, errors.Join(warnings...)
 }
 
-func (svc Services) EnsureTaxonomyPages(ctx context.Context, txq *db.Queries, page *db.Page) (err error) {
+// EnsureTaxonomyPages creates any missing series and topic pages for page
+// and adds their content files to files.
+func (svc Services) EnsureTaxonomyPages(ctx context.Context, txq *db.Queries, page *db.Page, files map[string][]byte) (err error) {
 	var errs []error
 	for _, name := range page.Series() {
-		path := fmt.Sprintf("content/series/%s/_index.md", name)
-		if e := svc.EnsureTaxonomyPage(ctx, path, name, txq, page); e != nil {
+		path := taxonomyPagePath("series", name)
+		if e := svc.EnsureTaxonomyPage(ctx, path, name, txq, page, files); e != nil {
 			errs = append(errs, e)
 		}
 	}
 	for _, name := range page.Topics() {
-		path := fmt.Sprintf("content/topics/%s/_index.md", name)
-		if e := svc.EnsureTaxonomyPage(ctx, path, name, txq, page); e != nil {
+		path := taxonomyPagePath("topics", name)
+		if e := svc.EnsureTaxonomyPage(ctx, path, name, txq, page, files); e != nil {
 			errs = append(errs, e)
 		}
 	}
 	return errors.Join(errs...)
 }
 
-func (svc Services) EnsureTaxonomyPage(ctx context.Context, path, name string, txq *db.Queries, src *db.Page) (err error) {
+func (svc Services) EnsureTaxonomyPage(ctx context.Context, path, name string, txq *db.Queries, src *db.Page, files map[string][]byte) (err error) {
 	defer errorx.Trace(&err)
 
 	// Skip if a row already exists.
@@ -208,8 +369,8 @@ func (svc Services) EnsureTaxonomyPage(ctx context.Context, path, name string, t
 	if err := index.Save(ctx, txq, true); err != nil {
 		return err
 	}
-	msg := fmt.Sprintf("Content: publishing %q", name)
-	return svc.ContentStore.UpdateFile(ctx, msg, index.FilePath, []byte(data))
+	files[index.FilePath] = []byte(data)
+	return nil
 }
 
 func (svc Services) RefreshPageContents(ctx context.Context, id int64) (err error) {
@@ -254,7 +415,7 @@ func (svc Services) RefreshPageContents(ctx context.Context, id int64) (err erro
 	l.InfoContext(ctx, "Services.RefreshPageContents: page changed",
 		"file_path", page.FilePath, "id", page.ID)
 
-	_, err = svc.Queries.UpdatePage(ctx, db.UpdatePageParams{
+	_, err = svc.Queries.UpdatePageWithRevision(ctx, db.UpdatePageParams{
 		ID:             page.ID,
 		SetFrontmatter: true,
 		Frontmatter:    page.Frontmatter,
@@ -286,6 +447,10 @@ func (svc Services) CreatePageFromGDocsDoc(ctx context.Context, shared *db.Share
 		// improve
 		return resperr.E{M: "Document must be processed before conversion."}
 	}
+	authors, err := svc.MatchAuthorNames(ctx, stringx.ExtractNames(shared.Byline))
+	if err != nil {
+		return err
+	}
 	body := dbDoc.ArticleMarkdown
 	slug := strings.ToLower(cmp.Or(
 		dbDoc.Metadata.URLSlug,
@@ -303,7 +468,7 @@ func (svc Services) CreatePageFromGDocsDoc(ctx context.Context, shared *db.Share
 		"internal-id":       shared.InternalID,
 		"published":         shared.PublicationDate.Time,
 		"byline":            shared.Byline,
-		"authors":           stringx.ExtractNames(shared.Byline),
+		"authors":           authors,
 		"title":             shared.Hed,
 		"description":       shared.Description,
 		"blurb":             shared.Blurb,
@@ -386,7 +551,7 @@ func (svc Services) Notify(ctx context.Context, page *db.Page, publishingNow boo
 	}
 
 	hed, _ := page.Frontmatter["title"].(string)
-	summary := page.Frontmatter["description"].(string)
+	summary, _ := page.Frontmatter["description"].(string)
 	url := page.FullURL()
 	l := almlog.FromContext(ctx)
 	return svc.SlackSocial.Post(ctx, l.InfoContext, svc.Client, slackhook.Message{
@@ -406,6 +571,37 @@ func (svc Services) Notify(ctx context.Context, page *db.Page, publishingNow boo
 	})
 }
 
+func (svc Services) NotifyUnpublished(ctx context.Context, page *db.Page, redirectTo string) (err error) {
+	defer errorx.Trace(&err)
+
+	const red = "#e02424"
+
+	hed, _ := page.Frontmatter["title"].(string)
+	hed = cmp.Or(hed, page.FilePath)
+	url := page.FullURL()
+	text := fmt.Sprintf("Unpublished by %s",
+		cmp.Or(netlifyid.FromContext(ctx).Email(), "Almanack"))
+	if redirectTo != "" {
+		text += fmt.Sprintf("\nNow redirecting to %s", redirectTo)
+	}
+	l := almlog.FromContext(ctx)
+	return svc.SlackSocial.Post(ctx, l.InfoContext, svc.Client, slackhook.Message{
+		Text: "Page taken down…",
+		Attachments: []slackhook.Attachment{
+			{
+				Color: red,
+				Fallback: fmt.Sprintf("%s\n%s\n%s",
+					hed, url, text),
+				Title:     hed,
+				TitleLink: url,
+				Text: fmt.Sprintf(
+					"%s\n%s",
+					url, text),
+			},
+		},
+	})
+}
+
 func (svc Services) PageLoadFromContentStore(ctx context.Context, path string) (page *db.Page, err error) {
 	defer errorx.Trace(&err)
 
@@ -2,11 +2,14 @@ package almsvc
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
 	"github.com/jackc/pgx/v5"
 	"github.com/spotlightpa/almanack/internal/almlog"
 	"github.com/spotlightpa/almanack/internal/db"
@@ -61,11 +64,39 @@ type ScheduledSiteConfig struct {
 	Data        db.Map    `json:"data"`
 }
 
-func (svc Services) UpdateSiteConfig(ctx context.Context, loc string, configs []ScheduledSiteConfig) ([]db.SiteDatum, error) {
+// ErrStaleSiteConfig means that the site configs were changed after the client loaded them.
+var ErrStaleSiteConfig = resperr.New(http.StatusConflict,
+	"Site data was changed by someone else. Reload to see their changes.")
+
+// SiteConfigETag returns an opaque version identifier for configs.
+func SiteConfigETag(configs []db.SiteDatum) string {
+	h := sha256.New()
+	for _, config := range configs {
+		fmt.Fprintf(h, "%d:%d\n", config.ID, config.UpdatedAt.UnixMicro())
+	}
+	return fmt.Sprintf(`"%x"`, h.Sum(nil)[:12])
+}
+
+// UpdateSiteConfig replaces the current and future configs for loc.
+// If ifMatch is set and does not match the ETag of the existing configs,
+// it returns ErrStaleSiteConfig.
+func (svc Services) UpdateSiteConfig(ctx context.Context, loc, ifMatch string, configs []ScheduledSiteConfig) ([]db.SiteDatum, error) {
 	var dbConfigs []db.SiteDatum
 	err := svc.DB.Tx(ctx, pgx.TxOptions{}, func(q *db.Queries) (txerr error) {
 		defer errorx.Trace(&txerr)
 
+		if ifMatch != "" && ifMatch != "*" {
+			if txerr = q.LockSiteData(ctx, loc); txerr != nil {
+				return txerr
+			}
+			current, txerr := q.GetSiteData(ctx, loc)
+			if txerr != nil {
+				return txerr
+			}
+			if SiteConfigETag(current) != ifMatch {
+				return ErrStaleSiteConfig
+			}
+		}
 		// Clear existing future entries before upserting current/future entries
 		if txerr = q.DeleteSiteData(ctx, loc); txerr != nil {
 			return txerr
@@ -0,0 +1,185 @@
+package almsvc
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+const (
+	// Google News only wants articles from the last two days
+	newsSitemapMaxAge = 48 * time.Hour
+	// newsSitemapLimit is the most URLs Google News allows in one sitemap
+	newsSitemapLimit = 1000
+
+	newsSitemapPath  = "sitemaps/sitemap-news.xml"
+	sitemapIndexPath = "sitemaps/sitemap.xml"
+	// sitemapPagesPath holds pages without a publication date, like section pages
+	sitemapPagesPath = "sitemaps/sitemap-pages.xml"
+
+	newsSitemapCacheControl = "public, max-age=300"
+	sitemapCacheControl     = "public, max-age=3600"
+)
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+	// lastMod is the latest change to any page in the sitemap
+	lastMod time.Time
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	XMLNS    string         `xml:"xmlns,attr"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type newsURLSet struct {
+	XMLName   xml.Name  `xml:"urlset"`
+	XMLNS     string    `xml:"xmlns,attr"`
+	XMLNSNews string    `xml:"xmlns:news,attr"`
+	URLs      []newsURL `xml:"url"`
+}
+
+type newsURL struct {
+	Loc  string      `xml:"loc"`
+	News newsArticle `xml:"news:news"`
+}
+
+type newsArticle struct {
+	Publication     newsPublication `xml:"news:publication"`
+	PublicationDate string          `xml:"news:publication_date"`
+	Title           string          `xml:"news:title"`
+}
+
+type newsPublication struct {
+	Name     string `xml:"news:name"`
+	Language string `xml:"news:language"`
+}
+
+const (
+	sitemapXMLNS     = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	newsSitemapXMLNS = "http://www.google.com/schemas/sitemap-news/0.9"
+)
+
+// UpdateSitemaps uploads a Google News sitemap of recent articles
+// and a sitemap index of every published page to the file store.
+// Pages are split into one sitemap per publication year,
+// so older sitemaps rarely change.
+// Files whose contents haven't changed are not uploaded again.
+func (svc Services) UpdateSitemaps(ctx context.Context) (err error) {
+	defer errorx.Trace(&err)
+
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "Services.UpdateSitemaps")
+
+	news, err := svc.Queries.ListNewsSitemapPages(ctx, db.ListNewsSitemapPagesParams{
+		PublishedAfter: time.Now().Add(-newsSitemapMaxAge),
+		Limit:          newsSitemapLimit,
+	})
+	if err != nil {
+		return err
+	}
+	uploaded := 0
+	upload := func(path, cacheControl string, sitemap any) error {
+		changed, err := UploadXML(ctx, svc.FileStore, path, cacheControl, sitemap)
+		if changed {
+			uploaded++
+		}
+		return err
+	}
+	if err = upload(newsSitemapPath, newsSitemapCacheControl, buildNewsSitemap(news)); err != nil {
+		return err
+	}
+
+	pages, err := svc.Queries.ListSitemapPages(ctx)
+	if err != nil {
+		return err
+	}
+	paths, sitemaps := buildSitemaps(pages)
+	index := sitemapIndex{
+		XMLNS:    sitemapXMLNS,
+		Sitemaps: make([]sitemapEntry, 0, len(paths)),
+	}
+	for _, path := range paths {
+		sitemap := sitemaps[path]
+		if err = upload(path, sitemapCacheControl, sitemap); err != nil {
+			return err
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+			Loc:     svc.FileStore.BuildURL(path),
+			LastMod: timex.ToEST(sitemap.lastMod).Format(time.RFC3339),
+		})
+	}
+	if err = upload(sitemapIndexPath, sitemapCacheControl, index); err != nil {
+		return err
+	}
+	l.InfoContext(ctx, "Services.UpdateSitemaps: done", "uploaded", uploaded)
+	return nil
+}
+
+func buildNewsSitemap(pages []db.ListNewsSitemapPagesRow) newsURLSet {
+	set := newsURLSet{
+		XMLNS:     sitemapXMLNS,
+		XMLNSNews: newsSitemapXMLNS,
+		URLs:      make([]newsURL, 0, len(pages)),
+	}
+	for _, page := range pages {
+		set.URLs = append(set.URLs, newsURL{
+			Loc: "https://www.spotlightpa.org" + page.URLPath,
+			News: newsArticle{
+				Publication: newsPublication{
+					Name:     "Spotlight PA",
+					Language: page.LanguageCode,
+				},
+				PublicationDate: timex.ToEST(page.PublicationDate).Format(time.RFC3339),
+				Title:           page.Title,
+			},
+		})
+	}
+	return set
+}
+
+// buildSitemaps groups pages into sitemaps by the year they were published,
+// returning the file store paths in order along with the sitemaps.
+func buildSitemaps(pages []db.ListSitemapPagesRow) (paths []string, sitemaps map[string]*sitemapURLSet) {
+	sitemaps = make(map[string]*sitemapURLSet)
+	for _, page := range pages {
+		path := sitemapPagesPath
+		if page.PublicationDate.Valid {
+			year := timex.ToEST(page.PublicationDate.Time).Year()
+			path = fmt.Sprintf("sitemaps/sitemap-%d.xml", year)
+		}
+		sitemap := sitemaps[path]
+		if sitemap == nil {
+			sitemap = &sitemapURLSet{XMLNS: sitemapXMLNS}
+			sitemaps[path] = sitemap
+			paths = append(paths, path)
+		}
+		sitemap.URLs = append(sitemap.URLs, sitemapURL{
+			Loc:     "https://www.spotlightpa.org" + page.URLPath,
+			LastMod: timex.ToEST(page.LastPublished).Format(time.RFC3339),
+		})
+		if page.LastPublished.After(sitemap.lastMod) {
+			sitemap.lastMod = page.LastPublished
+		}
+	}
+	return paths, sitemaps
+}
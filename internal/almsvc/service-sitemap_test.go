@@ -0,0 +1,110 @@
+package almsvc
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/aws"
+)
+
+func TestBuildNewsSitemap(t *testing.T) {
+	published := time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+	b, err := xml.MarshalIndent(buildNewsSitemap([]db.ListNewsSitemapPagesRow{{
+		URLPath:         "/news/2025/03/budget/",
+		PublicationDate: published,
+		Title:           "Budget & schools",
+		LanguageCode:    "es",
+	}}), "", "  ")
+	be.NilErr(t, err)
+	be.Equal(t, strings.TrimSpace(`
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9" xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url>
+    <loc>https://www.spotlightpa.org/news/2025/03/budget/</loc>
+    <news:news>
+      <news:publication>
+        <news:name>Spotlight PA</news:name>
+        <news:language>es</news:language>
+      </news:publication>
+      <news:publication_date>2025-03-04T10:00:00-05:00</news:publication_date>
+      <news:title>Budget &amp; schools</news:title>
+    </news:news>
+  </url>
+</urlset>`), string(b))
+}
+
+func TestBuildSitemaps(t *testing.T) {
+	date := func(year int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{
+			Time:  time.Date(year, 6, 1, 12, 0, 0, 0, time.UTC),
+			Valid: true,
+		}
+	}
+	// New Year's Eve in Pennsylvania is already January in UTC
+	newYearsEve := pgtype.Timestamptz{
+		Time:  time.Date(2025, 1, 1, 3, 0, 0, 0, time.UTC),
+		Valid: true,
+	}
+	older := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	paths, sitemaps := buildSitemaps([]db.ListSitemapPagesRow{
+		{URLPath: "/news/", LastPublished: older},
+		{URLPath: "/news/2023/a/", PublicationDate: date(2023), LastPublished: older},
+		{URLPath: "/news/2024/b/", PublicationDate: date(2024), LastPublished: newer},
+		{URLPath: "/news/2024/c/", PublicationDate: newYearsEve, LastPublished: older},
+	})
+	be.AllEqual(t, []string{
+		"sitemaps/sitemap-pages.xml",
+		"sitemaps/sitemap-2023.xml",
+		"sitemaps/sitemap-2024.xml",
+	}, paths)
+	be.Equal(t, 2, len(sitemaps["sitemaps/sitemap-2024.xml"].URLs))
+	be.Equal(t, "https://www.spotlightpa.org/news/2024/c/",
+		sitemaps["sitemaps/sitemap-2024.xml"].URLs[1].Loc)
+	be.Equal(t, newer, sitemaps["sitemaps/sitemap-2024.xml"].lastMod)
+}
+
+func TestUploadSitemap(t *testing.T) {
+	almlog.UseTestLogger(t)
+	ctx := t.Context()
+	dir := t.TempDir()
+	svc := Services{FileStore: aws.NewTestBlobStore(dir)}
+	sitemap := sitemapURLSet{
+		XMLNS: sitemapXMLNS,
+		URLs:  []sitemapURL{{Loc: "https://www.spotlightpa.org/news/a/"}},
+	}
+	modTime := func() time.Time {
+		t.Helper()
+		info, err := os.Stat(filepath.Join(dir, sitemapIndexPath))
+		be.NilErr(t, err)
+		return info.ModTime()
+	}
+
+	uploaded, err := UploadXML(ctx, svc.FileStore, sitemapIndexPath, sitemapCacheControl, sitemap)
+	be.NilErr(t, err)
+	be.True(t, uploaded)
+	written := modTime()
+
+	// The same contents aren't written again
+	uploaded, err = UploadXML(ctx, svc.FileStore, sitemapIndexPath, sitemapCacheControl, sitemap)
+	be.NilErr(t, err)
+	be.False(t, uploaded)
+	be.Equal(t, written, modTime())
+
+	// Header changes are uploaded too
+	uploaded, err = UploadXML(ctx, svc.FileStore, sitemapIndexPath, newsSitemapCacheControl, sitemap)
+	be.NilErr(t, err)
+	be.True(t, uploaded)
+
+	sitemap.URLs = append(sitemap.URLs, sitemapURL{Loc: "https://www.spotlightpa.org/news/b/"})
+	uploaded, err = UploadXML(ctx, svc.FileStore, sitemapIndexPath, sitemapCacheControl, sitemap)
+	be.NilErr(t, err)
+	be.True(t, uploaded)
+}
@@ -0,0 +1,297 @@
+package almsvc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/stringx"
+)
+
+// Taxonomies lists the frontmatter keys that Hugo treats as taxonomies.
+var Taxonomies = []string{"series", "topics"}
+
+func taxonomyPagePath(taxonomy, term string) string {
+	return fmt.Sprintf("content/%s/%s/_index.md", taxonomy, term)
+}
+
+// TaxonomyChange renames the terms in From to To.
+type TaxonomyChange struct {
+	Taxonomy string   `json:"taxonomy"`
+	From     []string `json:"from"`
+	To       string   `json:"to"`
+}
+
+// taxonomyJobBatchSize is the number of pages republished per commit.
+const taxonomyJobBatchSize = 25
+
+// CreateTaxonomyJob checks change and queues a job to apply it.
+// A rename must have a single source and a target that doesn't exist yet.
+// A merge may have several sources and an existing target.
+func (svc Services) CreateTaxonomyJob(ctx context.Context, change TaxonomyChange, merge bool) (job db.Job, err error) {
+	defer errorx.Trace(&err)
+
+	change.To = strings.TrimSpace(change.To)
+	var v resperr.Validator
+	v.AddIf("taxonomy", !slices.Contains(Taxonomies, change.Taxonomy),
+		"taxonomy must be one of %q", Taxonomies)
+	v.AddIf("to", change.To == "", "new name is required")
+	v.AddIf("to", strings.Contains(change.To, "/"), "new name may not contain a slash")
+	v.AddIf("from", len(change.From) == 0, "a term to change is required")
+	v.AddIf("from", !merge && len(change.From) > 1, "only one term can be renamed at a time")
+	for _, from := range change.From {
+		v.AddIf("from", strings.TrimSpace(from) == "", "terms may not be blank")
+		v.AddIf("from", from == change.To, "cannot change %q into itself", from)
+	}
+	if err = v.Err(); err != nil {
+		return job, err
+	}
+
+	if !merge {
+		_, err = svc.Queries.GetPageByFilePath(ctx, taxonomyPagePath(change.Taxonomy, change.To))
+		switch {
+		case err == nil:
+			return job, resperr.New(http.StatusConflict,
+				"%s %q already exists; merge into it instead", change.Taxonomy, change.To)
+		case !db.IsNotFound(err):
+			return job, err
+		}
+	}
+
+	kind := JobTaxonomyRename
+	if merge {
+		kind = JobTaxonomyMerge
+	}
+	return svc.CreateJob(ctx, kind, change)
+}
+
+func (svc Services) runTaxonomyRename(ctx context.Context, jp *JobProgress) error {
+	return svc.runTaxonomyChange(ctx, jp, false)
+}
+
+func (svc Services) runTaxonomyMerge(ctx context.Context, jp *JobProgress) error {
+	return svc.runTaxonomyChange(ctx, jp, true)
+}
+
+func (svc Services) runTaxonomyChange(ctx context.Context, jp *JobProgress, merge bool) (err error) {
+	defer errorx.Trace(&err)
+
+	var change TaxonomyChange
+	if err = jp.Params(&change); err != nil {
+		return err
+	}
+
+	// Move the landing pages first,
+	// so republishing doesn't create a blank landing page for the new term
+	var warnings []error
+	for _, from := range change.From {
+		warning, err := svc.moveTaxonomyPage(ctx, change.Taxonomy, from, change.To)
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, warning)
+	}
+
+	var pages []db.Page
+	for _, from := range change.From {
+		found, err := svc.Queries.ListPagesByTaxonomyTerm(ctx, db.ListPagesByTaxonomyTermParams{
+			Taxonomy: change.Taxonomy,
+			Term:     from,
+		})
+		if err != nil {
+			return err
+		}
+		for _, page := range found {
+			if !slices.ContainsFunc(pages, func(p db.Page) bool { return p.ID == page.ID }) {
+				pages = append(pages, page)
+			}
+		}
+	}
+	if err = jp.SetTotal(ctx, len(pages)); err != nil {
+		return err
+	}
+	if err = jp.Advance(ctx, 0, warnings...); err != nil {
+		return err
+	}
+
+	verb := "renaming"
+	if merge {
+		verb = "merging"
+	}
+	published := 0
+	for batch := range slices.Chunk(pages, taxonomyJobBatchSize) {
+		n, warning, err := svc.retagPages(ctx, batch, change,
+			fmt.Sprintf("Content: %s %s %q to %q",
+				verb, change.Taxonomy, strings.Join(change.From, `", "`), change.To))
+		if err != nil {
+			return err
+		}
+		published += n
+		if err = jp.Advance(ctx, len(batch), warning); err != nil {
+			return err
+		}
+	}
+	jp.SetResult(db.Map{
+		"pages":     len(pages),
+		"published": published,
+	})
+	return nil
+}
+
+// moveTaxonomyPage moves the landing page for a term.
+// If there is already a landing page for the new term,
+// the old one is unpublished and redirected to it.
+func (svc Services) moveTaxonomyPage(ctx context.Context, taxonomy, from, to string) (warning, err error) {
+	defer errorx.Trace(&err)
+
+	src, err := svc.Queries.GetPageByFilePath(ctx, taxonomyPagePath(taxonomy, from))
+	if db.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	wasPublished := src.LastPublished.Valid
+
+	var (
+		dst        db.Page
+		urlWarning error
+	)
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		dst, txerr = txq.GetPageByFilePath(ctx, taxonomyPagePath(taxonomy, to))
+		switch {
+		case db.IsNotFound(txerr):
+			dst = db.Page{
+				FilePath:    taxonomyPagePath(taxonomy, to),
+				SourceType:  src.SourceType,
+				SourceID:    src.SourceID,
+				Frontmatter: maps.Clone(src.Frontmatter),
+				Body:        src.Body,
+			}
+			for _, key := range []string{"title", "kicker", "linktitle"} {
+				if dst.Frontmatter[key] == from {
+					dst.Frontmatter[key] = to
+				}
+			}
+			dst.Frontmatter["slug"] = stringx.SlugifyURL(to)
+			if wasPublished {
+				dst.SetAliases(src.URLPath.String, "")
+			}
+			if txerr = dst.Save(ctx, txq, false); txerr != nil {
+				return txerr
+			}
+		case txerr != nil:
+			return txerr
+		}
+
+		if _, txerr = txq.UnpublishPage(ctx, src.ID); txerr != nil {
+			return txerr
+		}
+		if !wasPublished {
+			return nil
+		}
+		files := make(map[string][]byte)
+		if !dst.LastPublished.Valid {
+			if dst, txerr, urlWarning = svc.stagePagePublish(ctx, txq, &dst, files); txerr != nil {
+				return txerr
+			}
+		}
+		if src.URLPath.String != "" && dst.URLPath.String != "" {
+			if _, txerr = txq.UpsertRedirect(ctx, db.UpsertRedirectParams{
+				From:  src.URLPath.String,
+				To:    dst.URLPath.String,
+				Code:  http.StatusMovedPermanently,
+				Roles: []string{},
+			}); txerr != nil {
+				return txerr
+			}
+		}
+		msg := fmt.Sprintf("Content: moving %s %q to %q", taxonomy, from, to)
+		if txerr = svc.ContentStore.UpdateFiles(ctx, msg, files); txerr != nil {
+			return txerr
+		}
+		return svc.ContentStore.DeleteFile(ctx, msg, src.FilePath)
+	})
+	if err != nil || !wasPublished {
+		return nil, err
+	}
+	_, delErr := svc.Indexer.DeleteObject(src.FullURL(), ctx)
+	_, saveErr := svc.Indexer.SaveObject(dst.ToIndex(), ctx)
+	return errors.Join(urlWarning, delErr, saveErr), nil
+}
+
+// retagPages applies change to the frontmatter of pages
+// and republishes the published ones in a single commit.
+// It returns the number of pages republished.
+func (svc Services) retagPages(ctx context.Context, pages []db.Page, change TaxonomyChange, msg string) (n int, warning, err error) {
+	defer errorx.Trace(&err)
+
+	var (
+		published []db.Page
+		warnings  []error
+	)
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		defer errorx.Trace(&txerr)
+
+		published = published[:0]
+		warnings = warnings[:0]
+		files := make(map[string][]byte)
+		for _, page := range pages {
+			page.Frontmatter[change.Taxonomy] = replaceTerms(
+				stringx.UnwrapSlice(page.Frontmatter[change.Taxonomy]), change.From, change.To)
+			page, txerr = txq.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+				ID:             page.ID,
+				SetFrontmatter: true,
+				Frontmatter:    page.Frontmatter,
+				ScheduleFor:    db.NullTime,
+			})
+			if txerr != nil {
+				return txerr
+			}
+			if !page.LastPublished.Valid {
+				continue
+			}
+			var warning error
+			if page, txerr, warning = svc.stagePagePublish(ctx, txq, &page, files); txerr != nil {
+				return txerr
+			}
+			if warning != nil {
+				warnings = append(warnings, fmt.Errorf("%s: %w", page.FilePath, warning))
+			}
+			published = append(published, page)
+		}
+		return svc.ContentStore.UpdateFiles(ctx, msg, files)
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, page := range published {
+		_, indexErr := svc.Indexer.SaveObject(page.ToIndex(), ctx)
+		warnings = append(warnings, indexErr)
+	}
+	return len(published), errors.Join(warnings...), nil
+}
+
+// replaceTerms replaces any terms in from with to, without duplicating to.
+func replaceTerms(terms, from []string, to string) []string {
+	out := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if slices.Contains(from, term) {
+			term = to
+		}
+		if !slices.Contains(out, term) {
+			out = append(out, term)
+		}
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package almsvc
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+)
+
+func TestReplaceTerms(t *testing.T) {
+	cases := map[string]struct {
+		terms, from []string
+		to          string
+		want        []string
+	}{
+		"empty":     {nil, []string{"a"}, "b", []string{}},
+		"missing":   {[]string{"x", "y"}, []string{"a"}, "b", []string{"x", "y"}},
+		"rename":    {[]string{"x", "a", "y"}, []string{"a"}, "b", []string{"x", "b", "y"}},
+		"existing":  {[]string{"b", "a"}, []string{"a"}, "b", []string{"b"}},
+		"merge":     {[]string{"a", "x", "c"}, []string{"a", "c"}, "b", []string{"b", "x"}},
+		"untouched": {[]string{"A"}, []string{"a"}, "b", []string{"A"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			be.AllEqual(t, tc.want, replaceTerms(tc.terms, tc.from, tc.to))
+		})
+	}
+}
@@ -22,6 +22,8 @@ type Services struct {
 	Auth                 netlifyid.AuthService
 	MailchimpSignupURL   string
 	NetlifyWebhookSecret string
+	CalendarToken        string
+	BlockUnresolvedGDocs bool
 	Client               *http.Client
 	DB                   *db.Handle
 	Queries              *db.Queries
@@ -3,6 +3,7 @@ package blocko
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/earthboundkid/xhtml"
@@ -40,24 +41,30 @@ func blockToStrings(p *html.Node) []string {
 		counter := 0
 		if p.DataAtom == atom.Ol {
 			counter = 1
+			if start, err := strconv.Atoi(xhtml.Attr(p, "start")); err == nil {
+				counter = start
+			}
 		}
 		var blocks []string
 		for li := p.FirstChild; li != nil; li = li.NextSibling {
 			marker := "- "
-			if counter > 0 {
+			if p.DataAtom == atom.Ol {
 				marker = fmt.Sprintf("%d. ", counter)
 				counter++
 			}
+			// Continuation lines must line up with the item text
+			// to stay inside the item, including nested lists
+			indent := strings.Repeat(" ", len(marker))
 			for c := li.FirstChild; c != nil; c = c.NextSibling {
 				subblocks := blockToStrings(c)
 				for i := range subblocks {
 					if i > 0 {
-						marker = "  "
+						marker = indent
 					}
 					subblocks[i] = marker + subblocks[i]
 				}
 				blocks = append(blocks, subblocks...)
-				marker = "  "
+				marker = indent
 			}
 		}
 		return blocks
@@ -112,8 +112,12 @@ func fixBareLI(root *html.Node) {
 				InlineElements[child.DataAtom])
 	})
 	for _, li := range bareLIs {
+		// Wrap the leading text, leaving nested lists and other blocks in place
 		p := xhtml.New("p")
-		xhtml.AdoptChildren(p, li)
-		li.AppendChild(p)
+		for c := li.FirstChild; c != nil && !BlockElements[c.DataAtom]; c = li.FirstChild {
+			li.RemoveChild(c)
+			p.AppendChild(c)
+		}
+		li.InsertBefore(p, li.FirstChild)
 	}
 }
@@ -31,3 +31,9 @@ func IsUniquenessViolation(err error, constraintName string) bool {
 		pgErr.Code == pgerrcode.UniqueViolation &&
 		(constraintName == "" || pgErr.ConstraintName == constraintName)
 }
+
+// IsForeignKeyViolation tells whether the error was caused by a reference to a missing row.
+func IsForeignKeyViolation(err error) bool {
+	pgErr, ok := errors.AsType[*pgconn.PgError](err)
+	return ok && pgErr.Code == pgerrcode.ForeignKeyViolation
+}
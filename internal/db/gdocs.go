@@ -33,6 +33,9 @@ const (
 	RawEmbedTag        EmbedType = "raw"
 	ToCEmbedTag        EmbedType = "toc"
 	PartnerRawEmbedTag EmbedType = "partner-embed"
+	PullquoteEmbedTag  EmbedType = "pullquote"
+	CalloutEmbedTag    EmbedType = "callout"
+	RelatedEmbedTag    EmbedType = "related"
 )
 
 type EmbedType string
@@ -59,6 +62,24 @@ func (em *Embed) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		em.Value = img
+	case PullquoteEmbedTag:
+		var quote EmbedPullquote
+		if err := json.Unmarshal(temp.Value, &quote); err != nil {
+			return err
+		}
+		em.Value = quote
+	case CalloutEmbedTag:
+		var callout EmbedCallout
+		if err := json.Unmarshal(temp.Value, &callout); err != nil {
+			return err
+		}
+		em.Value = callout
+	case RelatedEmbedTag:
+		var related EmbedRelated
+		if err := json.Unmarshal(temp.Value, &related); err != nil {
+			return err
+		}
+		em.Value = related
 	case RawEmbedTag, ToCEmbedTag, PartnerRawEmbedTag:
 		var s string
 		if err := json.Unmarshal(temp.Value, &s); err != nil {
@@ -81,3 +102,24 @@ type EmbedImage struct {
 	Kind        string `json:"kind"`
 	Focus       string `json:"focus,omitzero"`
 }
+
+type EmbedPullquote struct {
+	Quote       string `json:"quote"`
+	Attribution string `json:"attribution"`
+}
+
+// EmbedCallout is a titled box set apart from the story.
+// Kind is "callout" or "factbox" and Body is HTML.
+type EmbedCallout struct {
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// EmbedRelated links to another story.
+// Hed and Image are filled in from the page table for links to our own pages.
+type EmbedRelated struct {
+	URL   string `json:"url"`
+	Hed   string `json:"hed"`
+	Image string `json:"image"`
+}
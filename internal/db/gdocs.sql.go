@@ -7,24 +7,26 @@ package db
 
 import (
 	"context"
+	"time"
 
 	docs "google.golang.org/api/docs/v1"
 )
 
 const createGDocsDoc = `-- name: CreateGDocsDoc :one
-INSERT INTO g_docs_doc ("external_id", "document")
-  VALUES ($1, $2)
+INSERT INTO g_docs_doc ("external_id", "document", "unresolved")
+  VALUES ($1, $2, coalesce($3::text[], '{}'))
 RETURNING
-  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at
+  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at, unresolved
 `
 
 type CreateGDocsDocParams struct {
 	ExternalID string        `json:"external_id"`
 	Document   docs.Document `json:"document"`
+	Unresolved []string      `json:"unresolved"`
 }
 
 func (q *Queries) CreateGDocsDoc(ctx context.Context, arg CreateGDocsDocParams) (GDocsDoc, error) {
-	row := q.db.QueryRow(ctx, createGDocsDoc, arg.ExternalID, arg.Document)
+	row := q.db.QueryRow(ctx, createGDocsDoc, arg.ExternalID, arg.Document, arg.Unresolved)
 	var i GDocsDoc
 	err := row.Scan(
 		&i.ID,
@@ -39,6 +41,7 @@ func (q *Queries) CreateGDocsDoc(ctx context.Context, arg CreateGDocsDocParams)
 		&i.Warnings,
 		&i.ProcessedAt,
 		&i.CreatedAt,
+		&i.Unresolved,
 	)
 	return i, err
 }
@@ -52,9 +55,37 @@ WHERE id NOT IN (
       shared_article
     WHERE
       source_type = 'gdocs')
+  AND id NOT IN (
+    SELECT DISTINCT ON (external_id)
+      id
+    FROM
+      g_docs_doc
+    WHERE
+      external_id IN (
+        SELECT
+          source_id
+        FROM
+          shared_article
+        WHERE
+          source_type = 'gdocs'
+          AND status IN ('P', 'S')
+        UNION
+        SELECT
+          source_id
+        FROM
+          page
+        WHERE
+          source_type = 'gdocs'
+          AND (expire_at IS NULL
+            OR expire_at > CURRENT_TIMESTAMP))
+    ORDER BY
+      external_id,
+      created_at DESC)
   AND processed_at < CURRENT_TIMESTAMP - interval '1 hour'
 `
 
+// DeleteGDocsDocWhereUnunused keeps the newest import of each document
+// that ListGDocsWatched watches so WatchGDocs can tell when it was last imported.
 func (q *Queries) DeleteGDocsDocWhereUnunused(ctx context.Context) error {
 	_, err := q.db.Exec(ctx, deleteGDocsDocWhereUnunused)
 	return err
@@ -62,7 +93,7 @@ func (q *Queries) DeleteGDocsDocWhereUnunused(ctx context.Context) error {
 
 const getGDocsByExternalIDWhereProcessed = `-- name: GetGDocsByExternalIDWhereProcessed :one
 SELECT
-  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at
+  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at, unresolved
 FROM
   g_docs_doc
 WHERE
@@ -89,13 +120,14 @@ func (q *Queries) GetGDocsByExternalIDWhereProcessed(ctx context.Context, extern
 		&i.Warnings,
 		&i.ProcessedAt,
 		&i.CreatedAt,
+		&i.Unresolved,
 	)
 	return i, err
 }
 
 const getGDocsByID = `-- name: GetGDocsByID :one
 SELECT
-  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at
+  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at, unresolved
 FROM
   g_docs_doc
 WHERE
@@ -118,6 +150,7 @@ func (q *Queries) GetGDocsByID(ctx context.Context, id int64) (GDocsDoc, error)
 		&i.Warnings,
 		&i.ProcessedAt,
 		&i.CreatedAt,
+		&i.Unresolved,
 	)
 	return i, err
 }
@@ -160,9 +193,67 @@ func (q *Queries) ListGDocsImagesByExternalID(ctx context.Context, externalID st
 	return items, nil
 }
 
+const listGDocsWatched = `-- name: ListGDocsWatched :many
+SELECT
+  external_id,
+  max(created_at)::timestamptz AS "last_imported_at"
+FROM
+  g_docs_doc
+WHERE
+  external_id IN (
+    SELECT
+      source_id
+    FROM
+      shared_article
+    WHERE
+      source_type = 'gdocs'
+      AND status IN ('P', 'S')
+    UNION
+    SELECT
+      source_id
+    FROM
+      page
+    WHERE
+      source_type = 'gdocs'
+      AND (expire_at IS NULL
+        OR expire_at > CURRENT_TIMESTAMP))
+GROUP BY
+  external_id
+ORDER BY
+  external_id
+`
+
+type ListGDocsWatchedRow struct {
+	ExternalID     string    `json:"external_id"`
+	LastImportedAt time.Time `json:"last_imported_at"`
+}
+
+// ListGDocsWatched returns the documents behind shared articles that are
+// still shared or in preview and pages that haven't expired
+// and when each was last imported.
+func (q *Queries) ListGDocsWatched(ctx context.Context) ([]ListGDocsWatchedRow, error) {
+	rows, err := q.db.Query(ctx, listGDocsWatched)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGDocsWatchedRow
+	for rows.Next() {
+		var i ListGDocsWatchedRow
+		if err := rows.Scan(&i.ExternalID, &i.LastImportedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listGDocsWhereUnprocessed = `-- name: ListGDocsWhereUnprocessed :many
 SELECT
-  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at
+  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at, unresolved
 FROM
   g_docs_doc
 WHERE
@@ -191,6 +282,7 @@ func (q *Queries) ListGDocsWhereUnprocessed(ctx context.Context) ([]GDocsDoc, er
 			&i.Warnings,
 			&i.ProcessedAt,
 			&i.CreatedAt,
+			&i.Unresolved,
 		); err != nil {
 			return nil, err
 		}
@@ -217,7 +309,7 @@ SET
 WHERE
   id = $8
 RETURNING
-  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at
+  id, external_id, document, metadata, embeds, rich_text, raw_html, article_markdown, word_count, warnings, processed_at, created_at, unresolved
 `
 
 type UpdateGDocsDocParams struct {
@@ -256,6 +348,7 @@ func (q *Queries) UpdateGDocsDoc(ctx context.Context, arg UpdateGDocsDocParams)
 		&i.Warnings,
 		&i.ProcessedAt,
 		&i.CreatedAt,
+		&i.Unresolved,
 	)
 	return i, err
 }
@@ -38,6 +38,23 @@ func TestEmbed_UnmarshalJSON(t *testing.T) {
 		be.NilErr(t, json.Unmarshal(b, &e2))
 		be.Equal(t, e1, e2)
 	}
+	for _, e1 := range []db.Embed{
+		{N: 3, Type: db.PullquoteEmbedTag, Value: db.EmbedPullquote{
+			Quote: "It's a disaster", Attribution: "State auditor",
+		}},
+		{N: 4, Type: db.CalloutEmbedTag, Value: db.EmbedCallout{
+			Kind: "factbox", Title: "By the numbers", Body: "<p>12</p>",
+		}},
+		{N: 5, Type: db.RelatedEmbedTag, Value: db.EmbedRelated{
+			URL: "https://www.spotlightpa.org/news/", Hed: "Hed", Image: "2024/01/a.jpeg",
+		}},
+	} {
+		b, err := json.Marshal(e1)
+		be.NilErr(t, err)
+		var e2 db.Embed
+		be.NilErr(t, json.Unmarshal(b, &e2))
+		be.Equal(t, e1, e2)
+	}
 	{
 		e1 := db.Embed{
 			Type: "bad",
@@ -29,6 +29,31 @@ type Arc struct {
 	UpdatedAt   time.Time          `json:"updated_at"`
 }
 
+type AuditEvent struct {
+	ID           int64           `json:"id"`
+	EmailAddress string          `json:"email_address"`
+	Route        string          `json:"route"`
+	Target       string          `json:"target"`
+	StatusCode   int32           `json:"status_code"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type Author struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Slug        string      `json:"slug"`
+	Title       string      `json:"title"`
+	Bio         string      `json:"bio"`
+	PhotoID     pgtype.Int8 `json:"photo_id"`
+	Email       string      `json:"email"`
+	SocialLinks Map         `json:"social_links"`
+	Active      bool        `json:"active"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
 type DomainRole struct {
 	ID        int64     `json:"id"`
 	Domain    string    `json:"domain"`
@@ -64,6 +89,7 @@ type GDocsDoc struct {
 	Warnings        []string           `json:"warnings"`
 	ProcessedAt     pgtype.Timestamptz `json:"processed_at"`
 	CreatedAt       time.Time          `json:"created_at"`
+	Unresolved      []string           `json:"unresolved"`
 }
 
 type GDocsImage struct {
@@ -97,6 +123,36 @@ type ImageType struct {
 	Extensions []string `json:"extensions"`
 }
 
+type Job struct {
+	ID         int64              `json:"id"`
+	Kind       string             `json:"kind"`
+	Params     Map                `json:"params"`
+	Result     Map                `json:"result"`
+	Total      int32              `json:"total"`
+	Processed  int32              `json:"processed"`
+	Warnings   []string           `json:"warnings"`
+	Error      string             `json:"error"`
+	CreatedBy  string             `json:"created_by"`
+	StartedAt  pgtype.Timestamptz `json:"started_at"`
+	FinishedAt pgtype.Timestamptz `json:"finished_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+type LinkCheck struct {
+	ID        int64              `json:"id"`
+	PageID    int64              `json:"page_id"`
+	URL       string             `json:"url"`
+	Source    string             `json:"source"`
+	Internal  bool               `json:"internal"`
+	Status    int32              `json:"status"`
+	Error     string             `json:"error"`
+	Broken    bool               `json:"broken"`
+	CheckedAt pgtype.Timestamptz `json:"checked_at"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
 type NewsFeedItem struct {
 	ID                  int64              `json:"id"`
 	ExternalID          string             `json:"external_id"`
@@ -140,6 +196,50 @@ type Page struct {
 	SourceType      string             `json:"source_type"`
 	SourceID        string             `json:"source_id"`
 	PublicationDate pgtype.Timestamptz `json:"publication_date"`
+	ExpireAt        pgtype.Timestamptz `json:"expire_at"`
+}
+
+type PageCorrection struct {
+	ID          int64     `json:"id"`
+	PageID      int64     `json:"page_id"`
+	Kind        string    `json:"kind"`
+	Note        string    `json:"note"`
+	CorrectedAt time.Time `json:"corrected_at"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PageLock struct {
+	PageID      int64     `json:"page_id"`
+	Email       string    `json:"email"`
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"created_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at"`
+}
+
+type PageRelated struct {
+	PageID    int64     `json:"page_id"`
+	RelatedID int64     `json:"related_id"`
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PageRevision struct {
+	ID            int64              `json:"id"`
+	PageID        int64              `json:"page_id"`
+	Frontmatter   Map                `json:"frontmatter"`
+	Body          string             `json:"body"`
+	ScheduleFor   pgtype.Timestamptz `json:"schedule_for"`
+	LastPublished pgtype.Timestamptz `json:"last_published"`
+	URLPath       string             `json:"url_path"`
+	CreatedBy     string             `json:"created_by"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type PageSourceChange struct {
+	PageID    int64     `json:"page_id"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
 type Redirect struct {
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PageLink is a link found in a page.
+type PageLink struct {
+	URL string `json:"url"`
+	// Source is body or the frontmatter key the link came from
+	Source string `json:"source"`
+	// Internal links point to a page on www.spotlightpa.org
+	Internal bool `json:"internal"`
+}
+
+// linkFrontmatterKeys are frontmatter keys that may hold a URL.
+var linkFrontmatterKeys = []string{"link", "image", "video-url"}
+
+var (
+	// The start of [text](url "title") and ![alt](url)
+	mdLinkStartRe = regexp.MustCompile(`\]\(\s*`)
+	// [ref]: url
+	mdRefRe = regexp.MustCompile(`(?m)^ {0,3}\[[^\]]+\]:\s*<?([^\s>]+)>?`)
+	// <https://example.com>
+	autolinkRe = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	// href="url" in HTML, or url="url" in a shortcode
+	attrLinkRe = regexp.MustCompile(`(?i)\b(?:href|src|url|link)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// Links returns the unique links in the page's body and link-like frontmatter.
+// Only absolute http(s) URLs and root-relative paths are returned;
+// relative paths, like image store keys, are skipped.
+func (page *Page) Links() []PageLink {
+	var links []PageLink
+	seen := make(map[string]bool)
+	add := func(raw, source string) {
+		u, internal, ok := NormalizeLink(raw)
+		if !ok || seen[u] {
+			return
+		}
+		seen[u] = true
+		links = append(links, PageLink{URL: u, Source: source, Internal: internal})
+	}
+	for _, key := range linkFrontmatterKeys {
+		if s, _ := page.Frontmatter[key].(string); s != "" {
+			add(s, key)
+		}
+	}
+	for _, s := range mdLinkDestinations(page.Body) {
+		add(s, "body")
+	}
+	for _, re := range []*regexp.Regexp{mdRefRe, autolinkRe, attrLinkRe} {
+		for _, m := range re.FindAllStringSubmatch(page.Body, -1) {
+			for _, s := range m[1:] {
+				if s != "" {
+					add(s, "body")
+				}
+			}
+		}
+	}
+	return links
+}
+
+// mdLinkDestinations returns the destinations of the inline links in body.
+// Like CommonMark, a destination may contain balanced parentheses,
+// as in Wikipedia URLs, or be wrapped in angle brackets.
+func mdLinkDestinations(body string) []string {
+	var dests []string
+	for _, loc := range mdLinkStartRe.FindAllStringIndex(body, -1) {
+		rest := body[loc[1]:]
+		if after, ok := strings.CutPrefix(rest, "<"); ok {
+			if end := strings.IndexAny(after, ">\n"); end > 0 && after[end] == '>' {
+				dests = append(dests, after[:end])
+			}
+			continue
+		}
+		depth, end := 0, len(rest)
+	scan:
+		for i, r := range rest {
+			switch {
+			case r == '(':
+				depth++
+			case r == ')' && depth == 0:
+				end = i
+				break scan
+			case r == ')':
+				depth--
+			case r == ' ', r == '\t', r == '\n':
+				end = i
+				break scan
+			}
+		}
+		if end > 0 {
+			dests = append(dests, rest[:end])
+		}
+	}
+	return dests
+}
+
+// NormalizeLink cleans up raw and reports whether it is a checkable link
+// and whether it points to a page on www.spotlightpa.org.
+// Internal links are returned as a path, without any query or fragment.
+// Links to files, like /embeds/map.html or /report.pdf, are not
+// treated as internal because they are not pages.
+func NormalizeLink(raw string) (link string, internal, ok bool) {
+	raw = strings.TrimSpace(html.UnescapeString(raw))
+	if strings.HasPrefix(raw, "//") {
+		raw = "https:" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", false, false
+	}
+	switch {
+	case u.Scheme == "" && u.Host == "" && strings.HasPrefix(u.Path, "/"):
+		u.Scheme = "https"
+		u.Host = "www.spotlightpa.org"
+	case u.Scheme == "http", u.Scheme == "https":
+		if u.Host == "" {
+			return "", false, false
+		}
+	default:
+		return "", false, false
+	}
+	u.Host = strings.ToLower(u.Host)
+	if (u.Host == "www.spotlightpa.org" || u.Host == "spotlightpa.org") &&
+		path.Ext(u.Path) == "" {
+		p := u.Path
+		if !strings.HasSuffix(p, "/") {
+			p += "/"
+		}
+		return p, true, true
+	}
+	u.Fragment = ""
+	return u.String(), false, true
+}
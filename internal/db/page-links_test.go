@@ -0,0 +1,78 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestPageLinks(t *testing.T) {
+	page := db.Page{
+		Frontmatter: db.Map{
+			"image":     "2024/01/abc.jpeg",
+			"link":      "https://example.com/story",
+			"video-url": "https://www.youtube.com/watch?v=123&t=4",
+		},
+		Body: `Read [the report](https://example.com/report.pdf "Report")
+and [our story](/news/2024/01/story/#top) or [this](https://SpotlightPA.org/about).
+
+![chart](https://files.data.spotlightpa.org/chart.png)
+See [Penn](https://en.wikipedia.org/wiki/Penn_(disambiguation)) and [the map](<https://example.com/map>).
+
+<a href="https://example.com/story?a=1&amp;b=2">raw</a>
+<a href='mailto:press@spotlightpa.org'>email</a>
+<a href="#footnote">jump</a>
+
+{{<embed/raw src="//cdn.example.com/widget.js">}}
+{{<picture src="2024/01/def.jpeg">}}
+{{<related url="/news/2024/01/story">}}
+
+See <https://example.org/>.
+
+[ref]: https://example.net/ref
+`,
+	}
+	got := page.Links()
+	want := []db.PageLink{
+		{"https://example.com/story", "link", false},
+		{"https://www.youtube.com/watch?v=123&t=4", "video-url", false},
+		{"https://example.com/report.pdf", "body", false},
+		{"/news/2024/01/story/", "body", true},
+		{"/about/", "body", true},
+		{"https://files.data.spotlightpa.org/chart.png", "body", false},
+		{"https://en.wikipedia.org/wiki/Penn_(disambiguation)", "body", false},
+		{"https://example.com/map", "body", false},
+		{"https://example.net/ref", "body", false},
+		{"https://example.org/", "body", false},
+		{"https://example.com/story?a=1&b=2", "body", false},
+		{"https://cdn.example.com/widget.js", "body", false},
+	}
+	be.AllEqual(t, want, got)
+}
+
+func TestNormalizeLink(t *testing.T) {
+	cases := []struct {
+		in       string
+		want     string
+		internal bool
+		ok       bool
+	}{
+		{"/news/", "/news/", true, true},
+		{"/news/2024/01/story", "/news/2024/01/story/", true, true},
+		{"https://www.spotlightpa.org/news/?utm=x#top", "/news/", true, true},
+		{"https://www.spotlightpa.org/embeds/map.html", "https://www.spotlightpa.org/embeds/map.html", false, true},
+		{"http://example.com/a#b", "http://example.com/a", false, true},
+		{"relative/path", "", false, false},
+		{"mailto:a@example.com", "", false, false},
+		{"tel:5555555555", "", false, false},
+		{"#top", "", false, false},
+		{"https://", "", false, false},
+	}
+	for _, tc := range cases {
+		got, internal, ok := db.NormalizeLink(tc.in)
+		be.Equal(t, tc.want, got)
+		be.Equal(t, tc.internal, internal)
+		be.Equal(t, tc.ok, ok)
+	}
+}
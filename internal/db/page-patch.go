@@ -0,0 +1,207 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FrontmatterOp is a JSON Patch (RFC 6902) style operation on frontmatter.
+// Paths are JSON Pointers to a key, like /byline,
+// or to an item in a list, like /authors/0 or /authors/- to append.
+//
+// Besides add, remove, replace, and test,
+// replace-text replaces Find with Value in a text value or a list of text.
+type FrontmatterOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+	Find  string `json:"find,omitempty"`
+}
+
+const (
+	PatchAdd         = "add"
+	PatchRemove      = "remove"
+	PatchReplace     = "replace"
+	PatchTest        = "test"
+	PatchReplaceText = "replace-text"
+)
+
+// ErrPatchTest means that a test operation did not match.
+var ErrPatchTest = errors.New("frontmatter did not match test")
+
+// Validate checks that op is well formed.
+func (op FrontmatterOp) Validate() error {
+	key, idx, err := op.splitPath()
+	if err != nil {
+		return err
+	}
+	switch op.Op {
+	case PatchAdd, PatchReplace, PatchTest:
+	case PatchRemove:
+		if idx == "-" {
+			return fmt.Errorf("cannot remove %q", op.Path)
+		}
+	case PatchReplaceText:
+		if idx != "" {
+			return fmt.Errorf("%s path must be a key: %q", op.Op, op.Path)
+		}
+		if op.Find == "" {
+			return fmt.Errorf("%s for %q needs text to find", op.Op, key)
+		}
+		if _, ok := op.Value.(string); !ok {
+			return fmt.Errorf("%s for %q needs replacement text", op.Op, key)
+		}
+	default:
+		return fmt.Errorf("unknown op %q", op.Op)
+	}
+	return nil
+}
+
+func (op FrontmatterOp) splitPath() (key, idx string, err error) {
+	rest, ok := strings.CutPrefix(op.Path, "/")
+	if !ok || rest == "" {
+		return "", "", fmt.Errorf("bad path %q", op.Path)
+	}
+	key, idx, _ = strings.Cut(rest, "/")
+	if strings.Contains(idx, "/") {
+		return "", "", fmt.Errorf("path is too deep: %q", op.Path)
+	}
+	unescape := strings.NewReplacer("~1", "/", "~0", "~").Replace
+	return unescape(key), idx, nil
+}
+
+// Patch applies ops in order to a copy of fm.
+// If a test op does not match, it returns ErrPatchTest.
+func (fm Map) Patch(ops []FrontmatterOp) (Map, error) {
+	out := maps.Clone(fm)
+	if out == nil {
+		out = Map{}
+	}
+	for _, op := range ops {
+		if err := out.applyOp(op); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func (fm Map) applyOp(op FrontmatterOp) error {
+	if err := op.Validate(); err != nil {
+		return err
+	}
+	key, idx, _ := op.splitPath()
+	cur, has := fm[key]
+
+	if op.Op == PatchReplaceText {
+		switch v := cur.(type) {
+		case string:
+			fm[key] = strings.ReplaceAll(v, op.Find, op.Value.(string))
+		case []any:
+			list := slices.Clone(v)
+			for i, item := range list {
+				if s, ok := item.(string); ok {
+					list[i] = strings.ReplaceAll(s, op.Find, op.Value.(string))
+				}
+			}
+			fm[key] = list
+		}
+		return nil
+	}
+
+	if idx == "" {
+		switch op.Op {
+		case PatchTest:
+			if !has || !jsonEqual(cur, op.Value) {
+				return ErrPatchTest
+			}
+		case PatchAdd:
+			fm[key] = op.Value
+		case PatchReplace, PatchRemove:
+			if !has {
+				return fmt.Errorf("%s: no value at %q", op.Op, op.Path)
+			}
+			if op.Op == PatchRemove {
+				delete(fm, key)
+			} else {
+				fm[key] = op.Value
+			}
+		}
+		return nil
+	}
+
+	var list []any
+	switch v := cur.(type) {
+	case nil:
+		if has || op.Op != PatchAdd {
+			return fmt.Errorf("%s: no list at %q", op.Op, key)
+		}
+	case []any:
+		list = slices.Clone(v)
+	case []string:
+		for _, s := range v {
+			list = append(list, s)
+		}
+	default:
+		return fmt.Errorf("%s: %q is not a list", op.Op, key)
+	}
+	i := len(list)
+	if idx != "-" {
+		n, err := strconv.Atoi(idx)
+		if err != nil || n < 0 || n > len(list) ||
+			(n == len(list) && op.Op != PatchAdd) {
+			return fmt.Errorf("%s: bad index in %q", op.Op, op.Path)
+		}
+		i = n
+	}
+	switch op.Op {
+	case PatchTest:
+		if i == len(list) || !jsonEqual(list[i], op.Value) {
+			return ErrPatchTest
+		}
+		return nil
+	case PatchAdd:
+		list = slices.Insert(list, i, op.Value)
+	case PatchReplace:
+		if i == len(list) {
+			return fmt.Errorf("%s: bad index in %q", op.Op, op.Path)
+		}
+		list[i] = op.Value
+	case PatchRemove:
+		list = slices.Delete(list, i, i+1)
+	}
+	fm[key] = list
+	return nil
+}
+
+// jsonEqual compares values as JSON would,
+// so that a []string from Go matches a []any decoded from JSON.
+func jsonEqual(a, b any) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(v any) any {
+	switch v := v.(type) {
+	case []string:
+		out := make([]any, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalizeJSON(item)
+		}
+		return out
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return v
+}
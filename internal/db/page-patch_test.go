@@ -0,0 +1,130 @@
+package db_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestMapPatch(t *testing.T) {
+	const fm = `{"byline":"By Jon Smtih","authors":["Jon Smtih","Ann"],"image-credit":"Old"}`
+	cases := map[string]struct {
+		ops  string
+		want string
+		err  error
+	}{
+		"empty": {`[]`, fm, nil},
+		"replace": {
+			`[{"op":"replace","path":"/image-credit","value":"New"}]`,
+			`{"authors":["Jon Smtih","Ann"],"byline":"By Jon Smtih","image-credit":"New"}`,
+			nil,
+		},
+		"add": {
+			`[{"op":"add","path":"/kicker","value":"News"}]`,
+			`{"authors":["Jon Smtih","Ann"],"byline":"By Jon Smtih","image-credit":"Old","kicker":"News"}`,
+			nil,
+		},
+		"remove": {
+			`[{"op":"remove","path":"/image-credit"}]`,
+			`{"authors":["Jon Smtih","Ann"],"byline":"By Jon Smtih"}`,
+			nil,
+		},
+		"append": {
+			`[{"op":"add","path":"/authors/-","value":"Bo"}]`,
+			`{"authors":["Jon Smtih","Ann","Bo"],"byline":"By Jon Smtih","image-credit":"Old"}`,
+			nil,
+		},
+		"insert": {
+			`[{"op":"add","path":"/authors/0","value":"Bo"}]`,
+			`{"authors":["Bo","Jon Smtih","Ann"],"byline":"By Jon Smtih","image-credit":"Old"}`,
+			nil,
+		},
+		"remove-item": {
+			`[{"op":"remove","path":"/authors/1"}]`,
+			`{"authors":["Jon Smtih"],"byline":"By Jon Smtih","image-credit":"Old"}`,
+			nil,
+		},
+		"new-list": {
+			`[{"op":"add","path":"/topics/-","value":"Politics"}]`,
+			`{"authors":["Jon Smtih","Ann"],"byline":"By Jon Smtih","image-credit":"Old","topics":["Politics"]}`,
+			nil,
+		},
+		"replace-text": {
+			`[{"op":"replace-text","path":"/byline","find":"Smtih","value":"Smith"},
+			  {"op":"replace-text","path":"/authors","find":"Smtih","value":"Smith"}]`,
+			`{"authors":["Jon Smith","Ann"],"byline":"By Jon Smith","image-credit":"Old"}`,
+			nil,
+		},
+		"test-pass": {
+			`[{"op":"test","path":"/authors","value":["Jon Smtih","Ann"]},
+			  {"op":"remove","path":"/byline"}]`,
+			`{"authors":["Jon Smtih","Ann"],"image-credit":"Old"}`,
+			nil,
+		},
+		"test-item": {
+			`[{"op":"test","path":"/authors/1","value":"Ann"}]`,
+			fm,
+			nil,
+		},
+		"test-fail": {
+			`[{"op":"test","path":"/image-credit","value":"Other"}]`,
+			``,
+			db.ErrPatchTest,
+		},
+		"test-missing": {
+			`[{"op":"test","path":"/kicker","value":"News"}]`,
+			``,
+			db.ErrPatchTest,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var in db.Map
+			be.NilErr(t, json.Unmarshal([]byte(fm), &in))
+			before, _ := json.Marshal(in)
+			var ops []db.FrontmatterOp
+			be.NilErr(t, json.Unmarshal([]byte(tc.ops), &ops))
+			got, err := in.Patch(ops)
+			if tc.err != nil {
+				be.True(t, errors.Is(err, tc.err))
+				return
+			}
+			be.NilErr(t, err)
+			b, err := json.Marshal(got)
+			be.NilErr(t, err)
+			var want db.Map
+			be.NilErr(t, json.Unmarshal([]byte(tc.want), &want))
+			wantb, _ := json.Marshal(want)
+			be.Equal(t, string(wantb), string(b))
+			// The original is untouched
+			after, _ := json.Marshal(in)
+			be.Equal(t, string(before), string(after))
+		})
+	}
+}
+
+func TestFrontmatterOpValidate(t *testing.T) {
+	for _, op := range []db.FrontmatterOp{
+		{Op: "move", Path: "/a"},
+		{Op: "add", Path: "a"},
+		{Op: "add", Path: "/"},
+		{Op: "add", Path: "/a/b/c"},
+		{Op: "remove", Path: "/a/-"},
+		{Op: "replace-text", Path: "/a", Value: "x"},
+		{Op: "replace-text", Path: "/a", Find: "x"},
+		{Op: "replace-text", Path: "/a/0", Find: "x", Value: "y"},
+	} {
+		be.Nonzero(t, op.Validate())
+	}
+	for _, op := range []db.FrontmatterOp{
+		{Op: "add", Path: "/a"},
+		{Op: "add", Path: "/a/-"},
+		{Op: "remove", Path: "/a/0"},
+		{Op: "replace-text", Path: "/a", Find: "x", Value: ""},
+	} {
+		be.NilErr(t, op.Validate())
+	}
+}
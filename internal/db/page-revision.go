@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/earthboundkid/errorx/v2"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+	"github.com/spotlightpa/almanack/internal/utils/stringx"
+)
+
+// UpdatePageWithRevision calls UpdatePage and then records the updated page
+// as a PageRevision attributed to the user in ctx, if any.
+func (q *Queries) UpdatePageWithRevision(ctx context.Context, arg UpdatePageParams) (page Page, err error) {
+	defer errorx.Trace(&err)
+
+	page, err = q.UpdatePage(ctx, arg)
+	if err != nil {
+		return
+	}
+	err = q.CreatePageRevision(ctx, CreatePageRevisionParams{
+		PageID:    page.ID,
+		CreatedBy: netlifyid.FromContext(ctx).Email(),
+	})
+	return
+}
+
+type FrontmatterChange struct {
+	Key    string `json:"key"`
+	Op     string `json:"op"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+const (
+	FrontmatterAdded   = "added"
+	FrontmatterRemoved = "removed"
+	FrontmatterChanged = "changed"
+)
+
+// PageRevisionDiff describes the changes needed to go from one PageRevision to another.
+type PageRevisionDiff struct {
+	FromID              int64               `json:"from_id"`
+	ToID                int64               `json:"to_id"`
+	Frontmatter         []FrontmatterChange `json:"frontmatter"`
+	BodyChanged         bool                `json:"body_changed"`
+	Body                []stringx.LineDiff  `json:"body"`
+	ScheduleForChanged  bool                `json:"schedule_for_changed"`
+	PublishStateChanged bool                `json:"publish_state_changed"`
+}
+
+func DiffPageRevisions(from, to *PageRevision) PageRevisionDiff {
+	diff := PageRevisionDiff{
+		FromID:      from.ID,
+		ToID:        to.ID,
+		Frontmatter: DiffFrontmatter(from.Frontmatter, to.Frontmatter),
+		BodyChanged: from.Body != to.Body,
+		Body:        stringx.DiffLines(from.Body, to.Body),
+		ScheduleForChanged: from.ScheduleFor.Valid != to.ScheduleFor.Valid ||
+			!from.ScheduleFor.Time.Equal(to.ScheduleFor.Time),
+		PublishStateChanged: from.LastPublished.Valid != to.LastPublished.Valid,
+	}
+	return diff
+}
+
+// DiffFrontmatter lists the keys that differ between two frontmatter maps, sorted by key.
+func DiffFrontmatter(from, to Map) []FrontmatterChange {
+	keys := slices.Collect(maps.Keys(from))
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	changes := []FrontmatterChange{}
+	for _, key := range keys {
+		before, hadBefore := from[key]
+		after, hasAfter := to[key]
+		switch {
+		case !hadBefore:
+			changes = append(changes, FrontmatterChange{
+				Key: key, Op: FrontmatterAdded, After: after,
+			})
+		case !hasAfter:
+			changes = append(changes, FrontmatterChange{
+				Key: key, Op: FrontmatterRemoved, Before: before,
+			})
+		case !reflect.DeepEqual(before, after):
+			changes = append(changes, FrontmatterChange{
+				Key: key, Op: FrontmatterChanged, Before: before, After: after,
+			})
+		}
+	}
+	return changes
+}
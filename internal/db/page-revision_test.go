@@ -0,0 +1,58 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestDiffPageRevisions(t *testing.T) {
+	from := db.PageRevision{
+		ID: 1,
+		Frontmatter: db.Map{
+			"title":   "Hello",
+			"byline":  "Jon Smith",
+			"topics":  []any{"Elections"},
+			"removed": "x",
+		},
+		Body: "one\ntwo\nthree",
+	}
+	to := db.PageRevision{
+		ID: 2,
+		Frontmatter: db.Map{
+			"title":  "Hello",
+			"byline": "John Smith",
+			"topics": []any{"Elections", "Courts"},
+			"added":  true,
+		},
+		Body: "one\n2\nthree",
+	}
+	diff := db.DiffPageRevisions(&from, &to)
+	be.Equal(t, 1, diff.FromID)
+	be.Equal(t, 2, diff.ToID)
+	be.True(t, diff.BodyChanged)
+	be.False(t, diff.ScheduleForChanged)
+	be.False(t, diff.PublishStateChanged)
+
+	var keys, ops []string
+	for _, change := range diff.Frontmatter {
+		keys = append(keys, change.Key)
+		ops = append(ops, change.Op)
+	}
+	be.AllEqual(t, []string{"added", "byline", "removed", "topics"}, keys)
+	be.AllEqual(t, []string{
+		db.FrontmatterAdded,
+		db.FrontmatterChanged,
+		db.FrontmatterRemoved,
+		db.FrontmatterChanged,
+	}, ops)
+	be.Equal(t, "Jon Smith", diff.Frontmatter[1].Before)
+	be.Equal(t, "John Smith", diff.Frontmatter[1].After)
+
+	be.Equal(t, 4, len(diff.Body))
+
+	same := db.DiffPageRevisions(&from, &from)
+	be.Equal(t, 0, len(same.Frontmatter))
+	be.False(t, same.BodyChanged)
+}
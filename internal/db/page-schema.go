@@ -0,0 +1,190 @@
+package db
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/earthboundkid/resperr/v2"
+	"github.com/spotlightpa/almanack/internal/utils/timex"
+)
+
+// FieldType is the expected type of a frontmatter value.
+type FieldType int8
+
+const (
+	FieldString FieldType = iota + 1
+	FieldStrings
+	FieldTime
+	FieldBool
+)
+
+// FieldSchema describes one frontmatter key.
+type FieldSchema struct {
+	Type     FieldType
+	Required bool
+	// MaxLen is the maximum number of characters in a string
+	MaxLen int
+	// Allowed lists the permitted values of a string, if set
+	Allowed []string
+}
+
+// PageSchema maps frontmatter keys to their schema.
+// Keys that are not in the schema are not checked.
+type PageSchema map[string]FieldSchema
+
+// The page editor warns about lengths shorter than these,
+// but only these are enforced.
+const (
+	maxTitleTagLen    = 100
+	maxDescriptionLen = 300
+)
+
+var layouts = []string{"", "blank", "featured"}
+
+var articleSchema = PageSchema{
+	"title":             {Type: FieldString, Required: true},
+	"published":         {Type: FieldTime, Required: true},
+	"internal-id":       {Type: FieldString},
+	"kicker":            {Type: FieldString},
+	"linktitle":         {Type: FieldString},
+	"title-tag":         {Type: FieldString, MaxLen: maxTitleTagLen},
+	"og-title":          {Type: FieldString},
+	"twitter-title":     {Type: FieldString},
+	"description":       {Type: FieldString, MaxLen: maxDescriptionLen},
+	"blurb":             {Type: FieldString},
+	"byline":            {Type: FieldString},
+	"authors":           {Type: FieldStrings},
+	"topics":            {Type: FieldStrings},
+	"series":            {Type: FieldStrings},
+	"aliases":           {Type: FieldStrings},
+	"related":           {Type: FieldStrings},
+	"image":             {Type: FieldString},
+	"image-description": {Type: FieldString},
+	"slug":              {Type: FieldString},
+	"url":               {Type: FieldString},
+	"layout":            {Type: FieldString, Allowed: layouts},
+	"draft":             {Type: FieldBool},
+}
+
+var taxonomySchema = PageSchema{
+	"title":       {Type: FieldString, Required: true},
+	"published":   {Type: FieldTime},
+	"kicker":      {Type: FieldString},
+	"linktitle":   {Type: FieldString},
+	"title-tag":   {Type: FieldString, MaxLen: maxTitleTagLen},
+	"description": {Type: FieldString, MaxLen: maxDescriptionLen},
+	"image":       {Type: FieldString},
+	"slug":        {Type: FieldString},
+	"aliases":     {Type: FieldStrings},
+	"layout":      {Type: FieldString, Allowed: layouts},
+}
+
+var youtubeSchema = PageSchema{
+	"title":       {Type: FieldString, Required: true},
+	"published":   {Type: FieldTime, Required: true},
+	"youtube-id":  {Type: FieldString, Required: true},
+	"video-url":   {Type: FieldString, Required: true},
+	"video-type":  {Type: FieldString, Allowed: []string{"youtube-regular", "youtube-short"}},
+	"description": {Type: FieldString, MaxLen: maxDescriptionLen},
+	"image":       {Type: FieldString},
+	"draft":       {Type: FieldBool},
+}
+
+// PageSchemas maps content sections to their schema.
+var PageSchemas = map[string]PageSchema{
+	"news":         articleSchema,
+	"statecollege": articleSchema,
+	"berks":        articleSchema,
+	"sponsored":    articleSchema,
+	"series":       taxonomySchema,
+	"topics":       taxonomySchema,
+	"videos":       youtubeSchema,
+}
+
+// Section returns the content section of the page, e.g. "news" for content/news/x.md.
+func (page *Page) Section() string {
+	section, _, _ := strings.Cut(strings.TrimPrefix(page.FilePath, "content/"), "/")
+	return section
+}
+
+// Validate checks the page's frontmatter against the schema for its section.
+// Pages in sections without a schema are always valid.
+func (page *Page) Validate() error {
+	schema, ok := PageSchemas[page.Section()]
+	if !ok {
+		return nil
+	}
+	return schema.Validate(page.Frontmatter)
+}
+
+// Validate returns a resperr validation error with one field per invalid key.
+func (schema PageSchema) Validate(fm Map) error {
+	var v resperr.Validator
+	for key, field := range schema {
+		val, set := fm[key]
+		if !set || val == nil {
+			v.AddIf(key, field.Required, "%s is required", key)
+			continue
+		}
+		switch field.Type {
+		case FieldString:
+			s, ok := val.(string)
+			if !ok {
+				v.Add(key, "%s must be text", key)
+				continue
+			}
+			v.AddIf(key, field.Required && strings.TrimSpace(s) == "",
+				"%s is required", key)
+			n := utf8.RuneCountInString(s)
+			v.AddIf(key, field.MaxLen > 0 && n > field.MaxLen,
+				"%s is too long (%d/%d characters)", key, n, field.MaxLen)
+			v.AddIf(key, field.Allowed != nil && !slices.Contains(field.Allowed, s),
+				"%s must be one of %q", key, field.Allowed)
+		case FieldStrings:
+			s, ok := toStrings(val)
+			if !ok {
+				v.Add(key, "%s must be a list of text", key)
+				continue
+			}
+			v.AddIf(key, field.Required && len(s) == 0, "%s is required", key)
+		case FieldTime:
+			var ok bool
+			switch t := val.(type) {
+			case time.Time:
+				ok = true
+				v.AddIf(key, field.Required && t.IsZero(), "%s is required", key)
+			case string:
+				_, ok = timex.Unwrap(t)
+				if t == "" {
+					ok = true
+					v.AddIf(key, field.Required, "%s is required", key)
+				}
+			}
+			v.AddIf(key, !ok, "%s must be a date", key)
+		case FieldBool:
+			_, ok := val.(bool)
+			v.AddIf(key, !ok, "%s must be true or false", key)
+		}
+	}
+	return v.Err()
+}
+
+func toStrings(val any) ([]string, bool) {
+	switch v := val.(type) {
+	case []string:
+		return v, true
+	case []any:
+		s := make([]string, 0, len(v))
+		for _, item := range v {
+			str, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			s = append(s, str)
+		}
+		return s, true
+	}
+	return nil, false
+}
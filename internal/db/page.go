@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -39,7 +40,8 @@ func (page *Page) Save(ctx context.Context, txq *Queries, setLastPublished bool)
 	if setLastPublished || page.LastPublished.Valid {
 		page.SetURLPath()
 	}
-	updated, err := txq.UpdatePage(ctx, UpdatePageParams{
+	// This also records the first revision of a new page
+	updated, err := txq.UpdatePageWithRevision(ctx, UpdatePageParams{
 		ID:               page.ID,
 		SetFrontmatter:   len(page.Frontmatter) != 0,
 		Frontmatter:      page.Frontmatter,
@@ -147,10 +149,16 @@ func (page *Page) SetURLPath() {
 	if page.URLPath.Valid && page.URLPath.String != "" {
 		return
 	}
+	upath := page.CalcURLPath()
+	page.URLPath.String = upath
+	page.URLPath.Valid = upath != ""
+}
+
+// CalcURLPath returns the URL path implied by the page's file path and
+// frontmatter, ignoring any URL path already saved for the page.
+func (page *Page) CalcURLPath() string {
 	if u, _ := page.Frontmatter["url"].(string); u != "" {
-		page.URLPath.String = strings.ToLower(u)
-		page.URLPath.Valid = true
-		return
+		return strings.ToLower(u)
 	}
 	upath := page.FilePath
 	upath = strings.TrimPrefix(upath, "content")
@@ -173,9 +181,46 @@ func (page *Page) SetURLPath() {
 	if upath != "" && !strings.HasSuffix(upath, "/") {
 		upath += "/"
 	}
-	upath = strings.ToLower(strings.ReplaceAll(upath, " ", "-"))
-	page.URLPath.String = upath
-	page.URLPath.Valid = upath != ""
+	return strings.ToLower(strings.ReplaceAll(upath, " ", "-"))
+}
+
+// Aliases returns the aliases frontmatter as a slice of strings.
+func (page *Page) Aliases() []string {
+	switch v := page.Frontmatter["aliases"].(type) {
+	case []string:
+		return v
+	case []any:
+		aliases := make([]string, 0, len(v))
+		for _, alias := range v {
+			if s, _ := alias.(string); s != "" {
+				aliases = append(aliases, s)
+			}
+		}
+		return aliases
+	}
+	return nil
+}
+
+// SetAliases adds add to and removes remove from the aliases frontmatter.
+// It reports whether the aliases changed.
+func (page *Page) SetAliases(add, remove string) bool {
+	old := page.Aliases()
+	aliases := slices.DeleteFunc(slices.Clone(old), func(alias string) bool {
+		return remove != "" && strings.EqualFold(alias, remove)
+	})
+	if add != "" && !slices.ContainsFunc(aliases, func(alias string) bool {
+		return strings.EqualFold(alias, add)
+	}) {
+		aliases = append(aliases, add)
+	}
+	if slices.Equal(old, aliases) {
+		return false
+	}
+	if page.Frontmatter == nil {
+		page.Frontmatter = make(Map)
+	}
+	page.Frontmatter["aliases"] = aliases
+	return true
 }
 
 func (page *Page) FullURL() string {
@@ -206,7 +251,7 @@ func (page *Page) ToIndex() any {
 	topics, _ := page.Frontmatter["topics"].([]string)
 	series, _ := page.Frontmatter["series"].([]string)
 	linkTitle, _ := page.Frontmatter["linktitle"].(string)
-	aliases, _ := page.Frontmatter["aliases"].([]string)
+	aliases := page.Aliases()
 	rawContent, _ := page.Frontmatter["raw-content"].(string)
 
 	body := cmp.Or(page.Body, rawContent)
@@ -310,6 +355,10 @@ func (page *Page) ShouldNotify(oldPage *Page) bool {
 	return !timex.Equalish(oldPage.ScheduleFor, page.ScheduleFor)
 }
 
+func (page *Page) Authors() []string {
+	return stringx.UnwrapSlice(page.Frontmatter["authors"])
+}
+
 func (page *Page) Topics() []string {
 	return stringx.UnwrapSlice(page.Frontmatter["topics"])
 }
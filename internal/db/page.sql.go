@@ -16,7 +16,7 @@ const createPage = `-- name: CreatePage :one
 INSERT INTO page ("file_path", "source_type", "source_id")
   VALUES ($1, $2, $3)
 RETURNING
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 `
 
 type CreatePageParams struct {
@@ -41,13 +41,14 @@ func (q *Queries) CreatePage(ctx context.Context, arg CreatePageParams) (Page, e
 		&i.SourceType,
 		&i.SourceID,
 		&i.PublicationDate,
+		&i.ExpireAt,
 	)
 	return i, err
 }
 
 const getPageByFilePath = `-- name: GetPageByFilePath :one
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   "page"
 WHERE
@@ -70,13 +71,14 @@ func (q *Queries) GetPageByFilePath(ctx context.Context, filePath string) (Page,
 		&i.SourceType,
 		&i.SourceID,
 		&i.PublicationDate,
+		&i.ExpireAt,
 	)
 	return i, err
 }
 
 const getPageByID = `-- name: GetPageByID :one
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   "page"
 WHERE
@@ -99,13 +101,14 @@ func (q *Queries) GetPageByID(ctx context.Context, id int64) (Page, error) {
 		&i.SourceType,
 		&i.SourceID,
 		&i.PublicationDate,
+		&i.ExpireAt,
 	)
 	return i, err
 }
 
 const getPageByURLPath = `-- name: GetPageByURLPath :one
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   page
 WHERE
@@ -129,13 +132,14 @@ func (q *Queries) GetPageByURLPath(ctx context.Context, urlPath string) (Page, e
 		&i.SourceType,
 		&i.SourceID,
 		&i.PublicationDate,
+		&i.ExpireAt,
 	)
 	return i, err
 }
 
 const listAllSeries = `-- name: ListAllSeries :many
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   page
 WHERE
@@ -166,6 +170,7 @@ func (q *Queries) ListAllSeries(ctx context.Context) ([]Page, error) {
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -179,7 +184,7 @@ func (q *Queries) ListAllSeries(ctx context.Context) ([]Page, error) {
 
 const listAllTopics = `-- name: ListAllTopics :many
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   page
 WHERE
@@ -210,6 +215,7 @@ func (q *Queries) ListAllTopics(ctx context.Context) ([]Page, error) {
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -221,6 +227,50 @@ func (q *Queries) ListAllTopics(ctx context.Context) ([]Page, error) {
 	return items, nil
 }
 
+const listOtherPagesByURLPath = `-- name: ListOtherPagesByURLPath :many
+SELECT
+  id,
+  file_path
+FROM
+  page
+WHERE
+  RTRIM(url_path, '/')
+  ILIKE RTRIM($1::text, '/')
+  AND id <> $2
+ORDER BY
+  id ASC
+`
+
+type ListOtherPagesByURLPathParams struct {
+	URLPath string `json:"url_path"`
+	ID      int64  `json:"id"`
+}
+
+type ListOtherPagesByURLPathRow struct {
+	ID       int64  `json:"id"`
+	FilePath string `json:"file_path"`
+}
+
+func (q *Queries) ListOtherPagesByURLPath(ctx context.Context, arg ListOtherPagesByURLPathParams) ([]ListOtherPagesByURLPathRow, error) {
+	rows, err := q.db.Query(ctx, listOtherPagesByURLPath, arg.URLPath, arg.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOtherPagesByURLPathRow
+	for rows.Next() {
+		var i ListOtherPagesByURLPathRow
+		if err := rows.Scan(&i.ID, &i.FilePath); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPageIDs = `-- name: ListPageIDs :many
 SELECT
   "id"
@@ -259,10 +309,64 @@ func (q *Queries) ListPageIDs(ctx context.Context, arg ListPageIDsParams) ([]int
 	return items, nil
 }
 
+const listPageIDsByFilter = `-- name: ListPageIDsByFilter :many
+SELECT
+  "id"
+FROM
+  page
+WHERE
+  "file_path" LIKE $1::text
+  AND ($2::text = ''
+    OR frontmatter @> jsonb_build_object('topics', jsonb_build_array($2::text)))
+  AND ($3::text = ''
+    OR frontmatter @> jsonb_build_object('authors', jsonb_build_array($3::text))
+    OR frontmatter ->> 'byline' ILIKE '%' || $3::text || '%')
+  AND ($4::timestamptz IS NULL
+    OR publication_date >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL
+    OR publication_date < $5::timestamptz)
+ORDER BY
+  id ASC
+`
+
+type ListPageIDsByFilterParams struct {
+	FilePath        string             `json:"file_path"`
+	Topic           string             `json:"topic"`
+	Author          string             `json:"author"`
+	PublishedAfter  pgtype.Timestamptz `json:"published_after"`
+	PublishedBefore pgtype.Timestamptz `json:"published_before"`
+}
+
+func (q *Queries) ListPageIDsByFilter(ctx context.Context, arg ListPageIDsByFilterParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listPageIDsByFilter,
+		arg.FilePath,
+		arg.Topic,
+		arg.Author,
+		arg.PublishedAfter,
+		arg.PublishedBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPages = `-- name: ListPages :many
 WITH paths AS (
   SELECT
-    id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+    id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
   FROM
     page
   WHERE
@@ -270,7 +374,7 @@ WITH paths AS (
 ),
 ordered AS (
   SELECT
-    id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+    id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
   FROM
     paths
   ORDER BY
@@ -366,7 +470,7 @@ WITH query AS (
   LIMIT $1
 )
 SELECT
-  page.id, page.file_path, page.frontmatter, page.body, page.schedule_for, page.last_published, page.created_at, page.updated_at, page.url_path, page.source_type, page.source_id, page.publication_date
+  page.id, page.file_path, page.frontmatter, page.body, page.schedule_for, page.last_published, page.created_at, page.updated_at, page.url_path, page.source_type, page.source_id, page.publication_date, page.expire_at
 FROM
   page
   JOIN query USING (id)
@@ -401,6 +505,7 @@ func (q *Queries) ListPagesByFTS(ctx context.Context, arg ListPagesByFTSParams)
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -427,7 +532,7 @@ WITH query AS (
   LIMIT $1
 )
 SELECT
-  page.id, page.file_path, page.frontmatter, page.body, page.schedule_for, page.last_published, page.created_at, page.updated_at, page.url_path, page.source_type, page.source_id, page.publication_date
+  page.id, page.file_path, page.frontmatter, page.body, page.schedule_for, page.last_published, page.created_at, page.updated_at, page.url_path, page.source_type, page.source_id, page.publication_date, page.expire_at
 FROM
   page
   JOIN query USING (id)
@@ -462,6 +567,7 @@ func (q *Queries) ListPagesByInternalID(ctx context.Context, arg ListPagesByInte
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -475,7 +581,7 @@ func (q *Queries) ListPagesByInternalID(ctx context.Context, arg ListPagesByInte
 
 const listPagesByPublished = `-- name: ListPagesByPublished :many
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   page
 WHERE
@@ -512,6 +618,57 @@ func (q *Queries) ListPagesByPublished(ctx context.Context, arg ListPagesByPubli
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPagesByTaxonomyTerm = `-- name: ListPagesByTaxonomyTerm :many
+SELECT
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
+FROM
+  page
+WHERE
+  frontmatter @> jsonb_build_object($1::text, jsonb_build_array($2::text))
+ORDER BY
+  id ASC
+`
+
+type ListPagesByTaxonomyTermParams struct {
+	Taxonomy string `json:"taxonomy"`
+	Term     string `json:"term"`
+}
+
+func (q *Queries) ListPagesByTaxonomyTerm(ctx context.Context, arg ListPagesByTaxonomyTermParams) ([]Page, error) {
+	rows, err := q.db.Query(ctx, listPagesByTaxonomyTerm, arg.Taxonomy, arg.Term)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.FilePath,
+			&i.Frontmatter,
+			&i.Body,
+			&i.ScheduleFor,
+			&i.LastPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.URLPath,
+			&i.SourceType,
+			&i.SourceID,
+			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -530,7 +687,7 @@ WITH query_paths AS (
 ),
 page_paths AS (
   SELECT
-    id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+    id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
   FROM
     page
   WHERE
@@ -600,7 +757,7 @@ func (q *Queries) ListPagesByURLPaths(ctx context.Context, paths []string) ([]Li
 
 const listPagesWithFrontmatter = `-- name: ListPagesWithFrontmatter :many
 SELECT
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 FROM
   page
 WHERE
@@ -638,6 +795,108 @@ func (q *Queries) ListPagesWithFrontmatter(ctx context.Context, arg ListPagesWit
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishedPagesAfterID = `-- name: ListPublishedPagesAfterID :many
+SELECT
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
+FROM
+  page
+WHERE
+  last_published IS NOT NULL
+  AND id > $1::bigint
+ORDER BY
+  id ASC
+LIMIT $2
+`
+
+type ListPublishedPagesAfterIDParams struct {
+	AfterID int64 `json:"after_id"`
+	Limit   int32 `json:"limit"`
+}
+
+// ListPublishedPagesAfterID pages through published pages in ID order.
+func (q *Queries) ListPublishedPagesAfterID(ctx context.Context, arg ListPublishedPagesAfterIDParams) ([]Page, error) {
+	rows, err := q.db.Query(ctx, listPublishedPagesAfterID, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.FilePath,
+			&i.Frontmatter,
+			&i.Body,
+			&i.ScheduleFor,
+			&i.LastPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.URLPath,
+			&i.SourceType,
+			&i.SourceID,
+			&i.PublicationDate,
+			&i.ExpireAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const popExpiredPages = `-- name: PopExpiredPages :many
+UPDATE
+  page
+SET
+  last_published = NULL,
+  schedule_for = NULL,
+  expire_at = NULL
+WHERE
+  last_published IS NOT NULL
+  AND expire_at < CURRENT_TIMESTAMP
+RETURNING
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
+`
+
+func (q *Queries) PopExpiredPages(ctx context.Context) ([]Page, error) {
+	rows, err := q.db.Query(ctx, popExpiredPages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Page
+	for rows.Next() {
+		var i Page
+		if err := rows.Scan(
+			&i.ID,
+			&i.FilePath,
+			&i.Frontmatter,
+			&i.Body,
+			&i.ScheduleFor,
+			&i.LastPublished,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.URLPath,
+			&i.SourceType,
+			&i.SourceID,
+			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -658,7 +917,7 @@ WHERE
   last_published IS NULL
   AND schedule_for < (CURRENT_TIMESTAMP + '5 minutes'::interval)
 RETURNING
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 `
 
 func (q *Queries) PopScheduledPages(ctx context.Context) ([]Page, error) {
@@ -683,6 +942,7 @@ func (q *Queries) PopScheduledPages(ctx context.Context) ([]Page, error) {
 			&i.SourceType,
 			&i.SourceID,
 			&i.PublicationDate,
+			&i.ExpireAt,
 		); err != nil {
 			return nil, err
 		}
@@ -694,6 +954,39 @@ func (q *Queries) PopScheduledPages(ctx context.Context) ([]Page, error) {
 	return items, nil
 }
 
+const unpublishPage = `-- name: UnpublishPage :one
+UPDATE
+  page
+SET
+  last_published = NULL,
+  schedule_for = NULL
+WHERE
+  id = $1
+RETURNING
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
+`
+
+func (q *Queries) UnpublishPage(ctx context.Context, id int64) (Page, error) {
+	row := q.db.QueryRow(ctx, unpublishPage, id)
+	var i Page
+	err := row.Scan(
+		&i.ID,
+		&i.FilePath,
+		&i.Frontmatter,
+		&i.Body,
+		&i.ScheduleFor,
+		&i.LastPublished,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.URLPath,
+		&i.SourceType,
+		&i.SourceID,
+		&i.PublicationDate,
+		&i.ExpireAt,
+	)
+	return i, err
+}
+
 const updatePage = `-- name: UpdatePage :one
 UPDATE
   page
@@ -713,32 +1006,43 @@ SET
   ELSE
     schedule_for
   END,
-  url_path = CASE WHEN $7::text != '' THEN
-    $7
+  expire_at = CASE WHEN $7::boolean THEN
+    $8
+  ELSE
+    expire_at
+  END,
+  url_path = CASE WHEN $9::text != '' THEN
+    $9
   ELSE
     url_path
   END,
-  last_published = CASE WHEN $8::boolean THEN
+  last_published = CASE WHEN $10::boolean THEN
     CURRENT_TIMESTAMP
   ELSE
     last_published
   END
 WHERE
-  id = $9
+  id = $11
+  -- Optimistic concurrency: skip the update if someone else saved first
+  AND ($12::timestamptz IS NULL
+    OR updated_at = $12::timestamptz)
 RETURNING
-  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date
+  id, file_path, frontmatter, body, schedule_for, last_published, created_at, updated_at, url_path, source_type, source_id, publication_date, expire_at
 `
 
 type UpdatePageParams struct {
-	SetFrontmatter   bool               `json:"set_frontmatter"`
-	Frontmatter      Map                `json:"frontmatter"`
-	SetBody          bool               `json:"set_body"`
-	Body             string             `json:"body"`
-	SetScheduleFor   bool               `json:"set_schedule_for"`
-	ScheduleFor      pgtype.Timestamptz `json:"schedule_for"`
-	URLPath          string             `json:"url_path"`
-	SetLastPublished bool               `json:"set_last_published"`
-	ID               int64              `json:"id"`
+	SetFrontmatter    bool               `json:"set_frontmatter"`
+	Frontmatter       Map                `json:"frontmatter"`
+	SetBody           bool               `json:"set_body"`
+	Body              string             `json:"body"`
+	SetScheduleFor    bool               `json:"set_schedule_for"`
+	ScheduleFor       pgtype.Timestamptz `json:"schedule_for"`
+	SetExpireAt       bool               `json:"set_expire_at"`
+	ExpireAt          pgtype.Timestamptz `json:"expire_at"`
+	URLPath           string             `json:"url_path"`
+	SetLastPublished  bool               `json:"set_last_published"`
+	ID                int64              `json:"id"`
+	ExpectedUpdatedAt pgtype.Timestamptz `json:"expected_updated_at"`
 }
 
 func (q *Queries) UpdatePage(ctx context.Context, arg UpdatePageParams) (Page, error) {
@@ -749,9 +1053,12 @@ func (q *Queries) UpdatePage(ctx context.Context, arg UpdatePageParams) (Page, e
 		arg.Body,
 		arg.SetScheduleFor,
 		arg.ScheduleFor,
+		arg.SetExpireAt,
+		arg.ExpireAt,
 		arg.URLPath,
 		arg.SetLastPublished,
 		arg.ID,
+		arg.ExpectedUpdatedAt,
 	)
 	var i Page
 	err := row.Scan(
@@ -767,6 +1074,7 @@ func (q *Queries) UpdatePage(ctx context.Context, arg UpdatePageParams) (Page, e
 		&i.SourceType,
 		&i.SourceID,
 		&i.PublicationDate,
+		&i.ExpireAt,
 	)
 	return i, err
 }
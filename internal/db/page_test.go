@@ -2,11 +2,15 @@ package db_test
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/carlmjohnson/be"
 	"github.com/carlmjohnson/be/testfile"
+	"github.com/earthboundkid/resperr/v2"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/spotlightpa/almanack/internal/db"
 )
@@ -240,6 +244,25 @@ func TestSetURLPath(t *testing.T) {
 	}
 }
 
+func TestSetAliases(t *testing.T) {
+	var page db.Page
+	be.True(t, page.SetAliases("/news/2020/01/old/", "/news/2020/01/new/"))
+	be.AllEqual(t, []string{"/news/2020/01/old/"}, page.Aliases())
+	be.False(t, page.SetAliases("/news/2020/01/OLD/", ""))
+
+	// Frontmatter from JSON
+	page.Frontmatter = db.Map{"aliases": []any{"/a/", "/b/"}}
+	be.True(t, page.SetAliases("/b/", "/a/"))
+	be.AllEqual(t, []string{"/b/"}, page.Aliases())
+
+	// CalcURLPath ignores a saved path
+	page.Frontmatter["slug"] = "c"
+	page.FilePath = "content/pages/b.md"
+	page.URLPath.String = "/b/"
+	page.URLPath.Valid = true
+	be.Equal(t, "/pages/c/", page.CalcURLPath())
+}
+
 func TestShouldPublishShouldNotify(t *testing.T) {
 	past := pgtype.Timestamptz{
 		Valid: true}
@@ -373,3 +396,55 @@ func TestSeries(t *testing.T) {
 		be.AllEqual(t, tc.want, p.Series())
 	}
 }
+
+func TestPageValidate(t *testing.T) {
+	valid := db.Map{
+		"title":       "Hello",
+		"published":   "2020-01-01T00:00:00Z",
+		"description": "A page",
+		"topics":      []any{"Politics"},
+		"layout":      "featured",
+		"draft":       false,
+	}
+	cases := map[string]struct {
+		path   string
+		fm     db.Map
+		fields []string
+	}{
+		"valid":       {"content/news/a.md", valid, nil},
+		"no-schema":   {"content/pages/a.md", db.Map{"title": 1}, nil},
+		"empty":       {"content/berks/a.md", db.Map{}, []string{"published", "title"}},
+		"blank-title": {"content/news/a.md", db.Map{"title": " ", "published": time.Now()}, []string{"title"}},
+		"wrong-types": {"content/statecollege/a.md", db.Map{
+			"title":     "Hello",
+			"published": "yesterday",
+			"topics":    "Politics",
+			"draft":     "no",
+		}, []string{"draft", "published", "topics"}},
+		"too-long": {"content/sponsored/a.md", db.Map{
+			"title":       "Hello",
+			"published":   time.Now(),
+			"title-tag":   strings.Repeat("x", 101),
+			"description": strings.Repeat("é", 301),
+		}, []string{"description", "title-tag"}},
+		"layout": {"content/topics/a/_index.md", db.Map{
+			"title":  "Hello",
+			"layout": "fancy",
+		}, []string{"layout"}},
+		"youtube": {"content/videos/a.md", db.Map{
+			"title":      "Hello",
+			"published":  time.Now(),
+			"youtube-id": "abc",
+			"video-url":  "https://www.youtube.com/watch?v=abc",
+			"video-type": "youtube-long",
+		}, []string{"video-type"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			page := db.Page{FilePath: tc.path, Frontmatter: tc.fm}
+			err := page.Validate()
+			fields := slices.Sorted(maps.Keys(resperr.ValidationErrors(err)))
+			be.AllEqual(t, tc.fields, fields)
+		})
+	}
+}
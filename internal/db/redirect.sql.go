@@ -9,6 +9,16 @@ import (
 	"context"
 )
 
+const deleteRedirect = `-- name: DeleteRedirect :exec
+DELETE FROM "redirect"
+WHERE "from" = $1
+`
+
+func (q *Queries) DeleteRedirect(ctx context.Context, from string) error {
+	_, err := q.db.Exec(ctx, deleteRedirect, from)
+	return err
+}
+
 const getRedirect = `-- name: GetRedirect :one
 SELECT
   id, "from", "to", roles, code, created_at
@@ -31,3 +41,41 @@ func (q *Queries) GetRedirect(ctx context.Context, from string) (Redirect, error
 	)
 	return i, err
 }
+
+const upsertRedirect = `-- name: UpsertRedirect :one
+INSERT INTO "redirect" ("from", "to", "code", "roles")
+  VALUES ($1, $2, $3, $4)
+ON CONFLICT ("from")
+  DO UPDATE SET
+    "to" = EXCLUDED."to",
+    "code" = EXCLUDED.code,
+    "roles" = EXCLUDED.roles
+  RETURNING
+    id, "from", "to", roles, code, created_at
+`
+
+type UpsertRedirectParams struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Code  int32    `json:"code"`
+	Roles []string `json:"roles"`
+}
+
+func (q *Queries) UpsertRedirect(ctx context.Context, arg UpsertRedirectParams) (Redirect, error) {
+	row := q.db.QueryRow(ctx, upsertRedirect,
+		arg.From,
+		arg.To,
+		arg.Code,
+		arg.Roles,
+	)
+	var i Redirect
+	err := row.Scan(
+		&i.ID,
+		&i.From,
+		&i.To,
+		&i.Roles,
+		&i.Code,
+		&i.CreatedAt,
+	)
+	return i, err
+}
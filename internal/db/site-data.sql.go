@@ -120,6 +120,17 @@ func (q *Queries) ListSiteKeys(ctx context.Context) ([]string, error) {
 	return items, nil
 }
 
+const lockSiteData = `-- name: LockSiteData :exec
+SELECT
+  pg_advisory_xact_lock(hashtext($1::text))
+`
+
+// LockSiteData serializes edits to a key until the end of the transaction.
+func (q *Queries) LockSiteData(ctx context.Context, key string) error {
+	_, err := q.db.Exec(ctx, lockSiteData, key)
+	return err
+}
+
 const popScheduledSiteChanges = `-- name: PopScheduledSiteChanges :many
 UPDATE
   site_data
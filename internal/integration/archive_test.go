@@ -0,0 +1,222 @@
+package integration_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/carlmjohnson/requests"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/aws"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+)
+
+func TestArchive(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.Auth = netlifyid.MockAuthService{}
+	svc.FileStore = aws.NewTestBlobStore(t.ArtifactDir(), "file")
+	rb := newTestServer(t, svc)
+
+	// Archives are made and read by jobs, through the file store
+	runJob := func(path string, body any) db.Job {
+		t.Helper()
+		var job db.Job
+		be.NilErr(t, rb.Clone().
+			Path(path).
+			BodyJSON(body).
+			ToJSON(&job).
+			Fetch(ctx))
+		_ = svc.RunPendingJobs(ctx)
+		job, err := svc.Queries.GetJobByID(ctx, job.ID)
+		be.NilErr(t, err)
+		return job
+	}
+	upload := func(data []byte) string {
+		t.Helper()
+		name := "archives/uploads/" + t.Name() + ".tar.gz"
+		be.NilErr(t, svc.FileStore.WriteFile(ctx, name, http.Header{}, data))
+		return name
+	}
+
+	create := func(path, title string, publish bool) db.Page {
+		return saveTestPage(t, svc, db.Page{
+			FilePath: path,
+			Frontmatter: db.Map{
+				"title":     title,
+				"kicker":    "",
+				"published": time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).Format(time.RFC3339),
+			},
+			Body: "Lorem ipsum.",
+		}, publish)
+	}
+	story := create("content/archive/story.md", "Original title", true)
+	create("content/archive/draft.md", "Draft", false)
+	_, err := svc.UpdateSiteConfig(ctx, "data/archive.json", "", []almsvc.ScheduledSiteConfig{{
+		ScheduleFor: time.Now().Add(-time.Minute),
+		Data:        db.Map{"items": []any{"content/archive/story.md"}},
+	}})
+	be.NilErr(t, err)
+
+	// Export matches what was published
+	job := runJob("/api/archive-export", struct{}{})
+	be.Equal(t, "", job.Error)
+	archive, err := svc.FileStore.ReadFile(ctx, job.Result["path"].(string))
+	be.NilErr(t, err)
+	files := readArchive(t, archive)
+	published, err := svc.ContentStore.GetFile(ctx, story.FilePath)
+	be.NilErr(t, err)
+	be.Equal(t, published, files[story.FilePath])
+	_, ok := files["content/archive/draft.md"]
+	be.False(t, ok)
+	published, err = svc.ContentStore.GetFile(ctx, "data/archive.json")
+	be.NilErr(t, err)
+	be.Equal(t, published, files["data/archive.json"])
+
+	// Other tests share the database, so only import this test's files
+	restore := writeArchive(t, map[string]string{
+		story.FilePath:      files[story.FilePath],
+		"data/archive.json": files["data/archive.json"],
+	})
+
+	// Change the page, then preview restoring it
+	story.Frontmatter["title"] = "New title"
+	story.Body = "Changed."
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) error {
+		return story.Save(ctx, txq, false)
+	})
+	be.NilErr(t, err)
+
+	job = runJob("/api/archive-import", almsvc.ArchiveImportRequest{
+		Path:   upload(restore),
+		DryRun: true,
+	})
+	be.Equal(t, "", job.Error)
+	var report almsvc.ArchiveImport
+	b, err := json.Marshal(job.Result)
+	be.NilErr(t, err)
+	be.NilErr(t, json.Unmarshal(b, &report))
+	be.True(t, report.DryRun)
+	be.Equal(t, 1, len(report.Pages))
+	be.Equal(t, story.FilePath, report.Pages[0].FilePath)
+	be.Equal(t, story.ID, report.Pages[0].ID)
+	be.True(t, report.Pages[0].BodyChanged)
+	be.Equal(t, 1, len(report.Pages[0].Frontmatter))
+	be.Equal(t, "title", report.Pages[0].Frontmatter[0].Key)
+	be.Equal(t, 0, len(report.SiteData))
+	be.Equal(t, 1, report.Unchanged)
+
+	page, err := svc.Queries.GetPageByFilePath(ctx, story.FilePath)
+	be.NilErr(t, err)
+	be.Equal(t, "New title", page.Frontmatter["title"])
+
+	// Restore it for real
+	report, err = svc.ImportArchive(ctx, bytes.NewReader(restore), false)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(report.Pages))
+	page, err = svc.Queries.GetPageByFilePath(ctx, story.FilePath)
+	be.NilErr(t, err)
+	be.Equal(t, "Original title", page.Frontmatter["title"])
+	be.Equal(t, "Lorem ipsum.", page.Body)
+
+	// Importing again changes nothing
+	report, err = svc.ImportArchive(ctx, bytes.NewReader(restore), false)
+	be.NilErr(t, err)
+	be.Equal(t, 0, len(report.Pages))
+
+	// New pages and site data are created
+	added := writeArchive(t, map[string]string{
+		"content/archive/new.md": "+++\ntitle = \"Brand new\"\n+++\n\nHello.\n",
+		"data/archive.json":      `{"items": []}`,
+		"static/robots.txt":      "",
+	})
+	report, err = svc.ImportArchive(ctx, bytes.NewReader(added), false)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(report.Pages))
+	be.Equal(t, 0, report.Pages[0].ID)
+	be.AllEqual(t, []string{"data/archive.json"}, report.SiteData)
+	be.AllEqual(t, []string{"static/robots.txt"}, report.Skipped)
+
+	page, err = svc.Queries.GetPageByFilePath(ctx, "content/archive/new.md")
+	be.NilErr(t, err)
+	be.Equal(t, "Brand new", page.Frontmatter["title"])
+	be.Equal(t, "Hello.", page.Body)
+	be.True(t, page.LastPublished.Valid)
+
+	configs, err := svc.Queries.GetSiteData(ctx, "data/archive.json")
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(configs))
+
+	// An empty body in the archive clears the page body
+	emptied := writeArchive(t, map[string]string{
+		"content/archive/new.md": "+++\ntitle = \"Brand new\"\n+++\n",
+	})
+	report, err = svc.ImportArchive(ctx, bytes.NewReader(emptied), false)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(report.Pages))
+	be.True(t, report.Pages[0].BodyChanged)
+	page, err = svc.Queries.GetPageByFilePath(ctx, "content/archive/new.md")
+	be.NilErr(t, err)
+	be.Equal(t, "", page.Body)
+
+	// Bad archives are rejected
+	err = rb.Clone().
+		Path("/api/archive-import").
+		BodyJSON(almsvc.ArchiveImportRequest{Path: "../secrets.tar.gz"}).
+		Fetch(ctx)
+	be.True(t, requests.HasStatusErr(err, 400))
+	job = runJob("/api/archive-import", almsvc.ArchiveImportRequest{
+		Path: upload([]byte("not an archive")),
+	})
+	be.In(t, "not gzipped", job.Error)
+}
+
+func readArchive(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	be.NilErr(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		be.NilErr(t, err)
+		data, err := io.ReadAll(tr)
+		be.NilErr(t, err)
+		files[hdr.Name] = string(data)
+	}
+	return files
+}
+
+func writeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		be.NilErr(t, tw.WriteHeader(&tar.Header{
+			Name: name,
+			Mode: 0o644,
+			Size: int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		be.NilErr(t, err)
+	}
+	be.NilErr(t, tw.Close())
+	be.NilErr(t, gw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,71 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.Auth = netlifyid.MockAuthService{}
+	cl := newTestServer(t, svc)
+
+	// Reads are not audited, but role grants are
+	be.NilErr(t, cl.Clone().
+		Path("/api/authorized-domains").
+		Fetch(ctx))
+	be.NilErr(t, cl.Clone().
+		Path("/api/authorized-domains").
+		BodyJSON(map[string]any{"domain": "audit.example"}).
+		Fetch(ctx))
+	be.NilErr(t, cl.Clone().
+		Path("/api/authorized-domains").
+		BodyJSON(map[string]any{"domain": "audit.example", "remove": true}).
+		Fetch(ctx))
+
+	var res almsvc.AuditLogResult
+	be.NilErr(t, cl.Clone().
+		Path("/api/audit-log").
+		Param("target", "audit.example").
+		ToJSON(&res).
+		Fetch(ctx))
+	be.Equal(t, 2, len(res.Events))
+	be.Zero(t, res.NextCursor)
+
+	removal := res.Events[0]
+	be.Equal(t, "mock", removal.EmailAddress)
+	be.Equal(t, "POST /api/authorized-domains", removal.Route)
+	be.Equal(t, 200, removal.StatusCode)
+	be.Equal(t, `["editor"]`, string(removal.Before))
+	// After is the saved roles, not the request
+	be.NotIn(t, "editor", string(removal.After))
+	be.NotIn(t, "remove", string(removal.After))
+	be.Equal(t, "null", string(res.Events[1].Before))
+	be.Equal(t, `["editor"]`, string(res.Events[1].After))
+
+	// Failed requests are recorded too
+	err := cl.Clone().
+		Path("/api/authorized-domains").
+		BodyJSON(map[string]any{"domain": ""}).
+		Fetch(ctx)
+	be.Nonzero(t, err)
+	events, err := svc.ListAuditEvents(ctx, almsvc.AuditLogQuery{Email: "MOCK"})
+	be.NilErr(t, err)
+	be.Equal(t, 3, len(events.Events))
+	be.Equal(t, 400, events.Events[0].StatusCode)
+
+	// Without a saved row, After falls back to the request body
+	var after map[string]any
+	be.NilErr(t, json.Unmarshal(events.Events[0].After, &after))
+	be.Equal(t, "", after["domain"])
+	be.False(t, strings.Contains(events.Events[0].Route, "GET"))
+}
@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestAuthor(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	// Bad authors are rejected
+	_, err := svc.SaveAuthor(ctx, db.Author{
+		Name:        "Robin Authortest",
+		Email:       "nope",
+		SocialLinks: db.Map{"x": "ftp://x.com/robin"},
+	})
+	be.In(t, "email", err.Error())
+	be.In(t, "link for x must be a URL", err.Error())
+
+	author, err := svc.SaveAuthor(ctx, db.Author{
+		Name:        "  Robin Authortest ",
+		Title:       "Reporter",
+		Bio:         "Robin covers the capitol.",
+		SocialLinks: db.Map{"x": "https://x.com/robin"},
+		Active:      true,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, "robin-authortest", author.Slug)
+	data, err := svc.ContentStore.GetFile(ctx, "data/authors.json")
+	be.NilErr(t, err)
+	be.In(t, `"robin-authortest": {`, data)
+	be.In(t, "Robin covers the capitol.", data)
+
+	_, err = svc.SaveAuthor(ctx, db.Author{Name: "Robin Authortest"})
+	be.In(t, "already uses the slug", err.Error())
+
+	// Bylines match authors regardless of case
+	names, err := svc.MatchAuthorNames(ctx, []string{"ROBIN AUTHORTEST", "Someone Else"})
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{"Robin Authortest", "Someone Else"}, names)
+
+	// Publishing a page creates the author page
+	saveTestPage(t, svc, db.Page{
+		FilePath: "content/news/author-test.md",
+		Frontmatter: db.Map{
+			"title":     "Author test",
+			"published": time.Now().Format(time.RFC3339),
+			"authors":   names,
+		},
+		Body: "Lorem ipsum.",
+	}, true)
+	content, err := svc.ContentStore.GetFile(ctx, "content/authors/robin-authortest/_index.md")
+	be.NilErr(t, err)
+	be.In(t, `title = "Robin Authortest"`, content)
+	be.In(t, "Robin covers the capitol.", content)
+
+	// Updates are republished
+	author.Bio = "Robin covers the courts."
+	author, err = svc.SaveAuthor(ctx, author)
+	be.NilErr(t, err)
+	content, err = svc.ContentStore.GetFile(ctx, "content/authors/robin-authortest/_index.md")
+	be.NilErr(t, err)
+	be.In(t, "Robin covers the courts.", content)
+
+	// Authors with pages can't be renamed or deleted
+	author.Slug = "robin"
+	_, err = svc.SaveAuthor(ctx, author)
+	be.In(t, "cannot change the slug", err.Error())
+	err = svc.DeleteAuthor(ctx, author.ID)
+	be.In(t, "mark them inactive instead", err.Error())
+
+	mistake, err := svc.SaveAuthor(ctx, db.Author{Name: "Robbin Authortest"})
+	be.NilErr(t, err)
+	be.NilErr(t, svc.DeleteAuthor(ctx, mistake.ID))
+	data, err = svc.ContentStore.GetFile(ctx, "data/authors.json")
+	be.NilErr(t, err)
+	be.False(t, strings.Contains(data, "robbin-authortest"))
+}
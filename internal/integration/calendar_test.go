@@ -0,0 +1,69 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestCalendar(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	at := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	for i, path := range []string{
+		"content/news/calendar-a.md",
+		"content/news/calendar-b.md",
+		"content/statecollege/calendar-c.md",
+	} {
+		saveTestPage(t, svc, db.Page{
+			FilePath:    path,
+			Frontmatter: db.Map{"title": path},
+			ScheduleFor: pgtype.Timestamptz{
+				Time:  at.Add(time.Duration(i) * time.Minute),
+				Valid: true,
+			},
+		}, false)
+	}
+
+	// Scheduled publishing leaves schedule_for set,
+	// but a live page is only listed as published
+	live := saveTestPage(t, svc, db.Page{
+		FilePath: "content/statecollege/calendar-live.md",
+		Frontmatter: db.Map{
+			"title":     "content/statecollege/calendar-live.md",
+			"published": at.Add(3 * time.Minute).Format(time.RFC3339),
+		},
+		ScheduleFor: pgtype.Timestamptz{
+			Time:  at.Add(3 * time.Minute),
+			Valid: true,
+		},
+	}, false)
+	_, err := svc.Queries.UpdatePage(ctx, db.UpdatePageParams{
+		ID:               live.ID,
+		SetLastPublished: true,
+	})
+	be.NilErr(t, err)
+
+	events, err := svc.Calendar(ctx, time.Now(), time.Now().Add(48*time.Hour))
+	be.NilErr(t, err)
+	be.Equal(t, 4, len(events))
+	be.Equal(t, "content/news/calendar-a.md", events[0].Title)
+	be.Equal(t, "content/news/", events[0].Section)
+	be.In(t, "calendar-b.md", events[0].Warning)
+	be.In(t, "calendar-a.md", events[1].Warning)
+	be.Zero(t, events[2].Warning)
+	be.Equal(t, "published-page", events[3].Kind)
+	be.Equal(t, live.ID, events[3].ID)
+	be.Zero(t, events[3].Warning)
+
+	events, err = svc.Calendar(ctx, time.Now().Add(48*time.Hour), time.Now().Add(72*time.Hour))
+	be.NilErr(t, err)
+	be.Zero(t, len(events))
+}
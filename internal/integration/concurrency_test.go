@@ -0,0 +1,81 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestUpdatePageExpectedUpdatedAt(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+	svc := newTestServices(t)
+	q := svc.Queries
+
+	p := saveTestPage(t, svc, db.Page{FilePath: "content/news/concurrency.md"}, false)
+	loaded := pgtype.Timestamptz{Time: p.UpdatedAt, Valid: true}
+
+	// First editor saves
+	p2, err := q.UpdatePage(ctx, db.UpdatePageParams{
+		ID:                p.ID,
+		SetBody:           true,
+		Body:              "first",
+		ExpectedUpdatedAt: loaded,
+	})
+	be.NilErr(t, err)
+	be.Unequal(t, p.UpdatedAt, p2.UpdatedAt)
+
+	// Second editor has a stale copy
+	_, err = q.UpdatePage(ctx, db.UpdatePageParams{
+		ID:                p.ID,
+		SetBody:           true,
+		Body:              "second",
+		ExpectedUpdatedAt: loaded,
+	})
+	be.True(t, db.IsNotFound(err))
+
+	current, err := q.GetPageByID(ctx, p.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "first", current.Body)
+
+	// No precondition always saves
+	_, err = q.UpdatePage(ctx, db.UpdatePageParams{
+		ID:      p.ID,
+		SetBody: true,
+		Body:    "third",
+	})
+	be.NilErr(t, err)
+}
+
+func TestUpdateSiteConfigIfMatch(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+	svc := newTestServices(t)
+	const loc = "data/test.json"
+	configs := []almsvc.ScheduledSiteConfig{{
+		ScheduleFor: time.Now().Add(-time.Hour),
+		Data:        db.Map{"n": 1},
+	}}
+	dbConfigs, err := svc.UpdateSiteConfig(ctx, loc, "", configs)
+	be.NilErr(t, err)
+	etag := almsvc.SiteConfigETag(dbConfigs)
+
+	configs = append(configs, almsvc.ScheduledSiteConfig{
+		ScheduleFor: time.Now().Add(time.Hour),
+		Data:        db.Map{"n": 2},
+	})
+	_, err = svc.UpdateSiteConfig(ctx, loc, etag, configs)
+	be.NilErr(t, err)
+
+	// etag is now stale
+	_, err = svc.UpdateSiteConfig(ctx, loc, etag, configs)
+	be.ErrorIs(t, almsvc.ErrStaleSiteConfig, err)
+
+	_, err = svc.UpdateSiteConfig(ctx, loc, "*", configs)
+	be.NilErr(t, err)
+}
@@ -0,0 +1,66 @@
+package integration_test
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	docs "google.golang.org/api/docs/v1"
+)
+
+func TestGDocsRelatedEmbed(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	saveTestPage(t, svc, db.Page{
+		FilePath: "content/news/related-test.md",
+		Frontmatter: db.Map{
+			"title": "Agency paid millions to consultants",
+			"image": "2024/01/audit.jpeg",
+		},
+		URLPath: pgtype.Text{String: "/news/2024/01/audit-consultants/", Valid: true},
+	}, false)
+
+	cell := func(text string) *docs.TableCell {
+		return &docs.TableCell{
+			TableCellStyle: &docs.TableCellStyle{ColumnSpan: 1},
+			Content: []*docs.StructuralElement{{Paragraph: &docs.Paragraph{
+				ParagraphStyle: &docs.ParagraphStyle{NamedStyleType: "NORMAL_TEXT"},
+				Elements: []*docs.ParagraphElement{{
+					TextRun: &docs.TextRun{Content: text + "\n"},
+				}},
+			}}},
+		}
+	}
+	dbDoc, err := svc.Queries.CreateGDocsDoc(ctx, db.CreateGDocsDocParams{
+		ExternalID: "related-test",
+		Document: docs.Document{Title: "Related", Body: &docs.Body{
+			Content: []*docs.StructuralElement{{Table: &docs.Table{
+				TableRows: []*docs.TableRow{
+					{TableCells: []*docs.TableCell{cell("related")}},
+					{TableCells: []*docs.TableCell{
+						cell("url"),
+						cell("https://www.spotlightpa.org/news/2024/01/audit-consultants"),
+					}},
+				},
+			}}},
+		}},
+	})
+	be.NilErr(t, err)
+	be.NilErr(t, svc.ProcessGDocsDoc(ctx, dbDoc))
+	dbDoc, err = svc.Queries.GetGDocsByID(ctx, dbDoc.ID)
+	be.NilErr(t, err)
+
+	be.Equal(t, 0, len(dbDoc.Warnings))
+	be.Equal(t, 1, len(dbDoc.Embeds))
+	be.Equal(t, db.EmbedRelated{
+		URL:   "https://www.spotlightpa.org/news/2024/01/audit-consultants/",
+		Hed:   "Agency paid millions to consultants",
+		Image: "2024/01/audit.jpeg",
+	}, dbDoc.Embeds[0].Value.(db.EmbedRelated))
+	be.In(t, `{{<related url="https://www.spotlightpa.org/news/2024/01/audit-consultants/"`, dbDoc.ArticleMarkdown)
+}
@@ -0,0 +1,50 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	docs "google.golang.org/api/docs/v1"
+)
+
+func TestCheckSharingAllowed(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.BlockUnresolvedGDocs = true
+
+	dbDoc, err := svc.Queries.CreateGDocsDoc(ctx, db.CreateGDocsDocParams{
+		ExternalID: "unresolved-test",
+		Document:   docs.Document{Title: "Unresolved", Body: &docs.Body{}},
+		Unresolved: []string{`Open comment by Pat: "Source?".`},
+	})
+	be.NilErr(t, err)
+	be.NilErr(t, svc.ProcessGDocsDoc(ctx, dbDoc))
+	dbDoc, err = svc.Queries.GetGDocsByID(ctx, dbDoc.ID)
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{`Open comment by Pat: "Source?".`}, dbDoc.Warnings)
+
+	art, err := svc.UpsertSharedArticleForGDoc(ctx, &dbDoc, false)
+	be.NilErr(t, err)
+	be.NilErr(t, svc.CheckSharingAllowed(ctx, art, "U"))
+	err = svc.CheckSharingAllowed(ctx, art, "S")
+	be.In(t, "1 unresolved", err.Error())
+
+	// Refreshing with a clean document lifts the block
+	clean, err := svc.Queries.CreateGDocsDoc(ctx, db.CreateGDocsDocParams{
+		ExternalID: "unresolved-test",
+		Document:   docs.Document{Title: "Unresolved", Body: &docs.Body{}},
+	})
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{}, clean.Unresolved)
+	art.RawData, _ = json.Marshal(clean.ID)
+	be.NilErr(t, svc.CheckSharingAllowed(ctx, art, "S"))
+
+	svc.BlockUnresolvedGDocs = false
+	art.RawData, _ = json.Marshal(dbDoc.ID)
+	be.NilErr(t, svc.CheckSharingAllowed(ctx, art, "S"))
+}
@@ -0,0 +1,143 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+	docs "google.golang.org/api/docs/v1"
+)
+
+func TestGDocsWatch(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.Auth = netlifyid.MockAuthService{}
+	createDoc := func() db.GDocsDoc {
+		t.Helper()
+		dbDoc, err := svc.Queries.CreateGDocsDoc(ctx, db.CreateGDocsDocParams{
+			ExternalID: "watch-test",
+			Document:   docs.Document{Title: "Watched", Body: &docs.Body{}},
+		})
+		be.NilErr(t, err)
+		return dbDoc
+	}
+	linkedDoc := func(art *db.SharedArticle) (id int64) {
+		t.Helper()
+		art2, err := svc.Queries.GetSharedArticleByID(ctx, art.ID)
+		be.NilErr(t, err)
+		be.NilErr(t, json.Unmarshal(art2.RawData, &id))
+		return id
+	}
+
+	first := createDoc()
+	be.NilErr(t, svc.ProcessGDocs(ctx))
+	first, err := svc.Queries.GetGDocsByID(ctx, first.ID)
+	be.NilErr(t, err)
+	art, err := svc.UpsertSharedArticleForGDoc(ctx, &first, false)
+	be.NilErr(t, err)
+	page := saveTestPage(t, svc, db.Page{
+		FilePath:   "content/news/watch-test.md",
+		SourceType: "gdocs",
+		SourceID:   "watch-test",
+	}, false)
+
+	watched, err := svc.Queries.ListGDocsWatched(ctx)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(watched))
+	be.Equal(t, "watch-test", watched[0].ExternalID)
+	be.True(t, watched[0].LastImportedAt.Equal(first.CreatedAt))
+
+	// The watcher imports a new copy and flags the page
+	second := createDoc()
+	n, err := svc.Queries.UpsertPageSourceChanges(ctx, db.UpsertPageSourceChangesParams{
+		ChangedAt:  second.CreatedAt,
+		SourceType: "gdocs",
+		SourceID:   "watch-test",
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 1, n)
+	watched, err = svc.Queries.ListGDocsWatched(ctx)
+	be.NilErr(t, err)
+	be.True(t, watched[0].LastImportedAt.Equal(second.CreatedAt))
+
+	// Shared articles keep the copy that was reviewed
+	be.NilErr(t, svc.ProcessGDocs(ctx))
+	be.Equal(t, first.ID, linkedDoc(art))
+
+	// Refreshing the page leaves the flag until the page is saved
+	cl := newTestServer(t, svc)
+	var res struct {
+		SourceChangedAt *time.Time `json:"source_changed_at"`
+	}
+	getPage := cl.Clone().
+		Path("/api/page").
+		Param("by", "id").
+		Param("value", strconv.FormatInt(page.ID, 10)).
+		ToJSON(&res)
+	be.NilErr(t, getPage.Fetch(ctx))
+	be.Nonzero(t, res.SourceChangedAt)
+
+	var refreshed struct {
+		SourceRefreshedAt time.Time `json:"source_refreshed_at"`
+	}
+	be.NilErr(t, cl.Clone().
+		Path("/api/page-refresh").
+		BodyJSON(map[string]any{"id": strconv.FormatInt(page.ID, 10)}).
+		ToJSON(&refreshed).
+		Fetch(ctx))
+	be.True(t, refreshed.SourceRefreshedAt.Equal(second.CreatedAt))
+	res.SourceChangedAt = nil
+	be.NilErr(t, getPage.Fetch(ctx))
+	be.Nonzero(t, res.SourceChangedAt)
+
+	save := func(refreshedAt *time.Time) {
+		t.Helper()
+		be.NilErr(t, cl.Clone().
+			Path("/api/page").
+			BodyJSON(map[string]any{
+				"id":                  page.ID,
+				"set_body":            true,
+				"body":                "Refreshed",
+				"source_refreshed_at": refreshedAt,
+			}).
+			Fetch(ctx))
+		res.SourceChangedAt = nil
+		be.NilErr(t, getPage.Fetch(ctx))
+	}
+	// Saves that don't carry the refreshed content leave the flag
+	save(nil)
+	be.Nonzero(t, res.SourceChangedAt)
+	save(&first.CreatedAt)
+	be.Nonzero(t, res.SourceChangedAt)
+
+	save(&refreshed.SourceRefreshedAt)
+	be.Zero(t, res.SourceChangedAt)
+
+	// Expired pages and unshared articles aren't watched
+	_, err = svc.Queries.UpdatePage(ctx, db.UpdatePageParams{
+		ID:          page.ID,
+		SetExpireAt: true,
+		ExpireAt:    pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	be.NilErr(t, err)
+	watched, err = svc.Queries.ListGDocsWatched(ctx)
+	be.NilErr(t, err)
+	be.Equal(t, 0, len(watched))
+
+	_, err = svc.Queries.UpdateSharedArticle(ctx, db.UpdateSharedArticleParams{
+		ID:     art.ID,
+		Status: "S",
+	})
+	be.NilErr(t, err)
+	watched, err = svc.Queries.ListGDocsWatched(ctx)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(watched))
+}
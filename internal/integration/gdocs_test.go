@@ -12,7 +12,6 @@ import (
 	"github.com/carlmjohnson/be/testfile"
 	"github.com/carlmjohnson/requests/reqtest"
 	"github.com/spotlightpa/almanack/internal/almlog"
-	"github.com/spotlightpa/almanack/internal/almsvc"
 	"github.com/spotlightpa/almanack/internal/db"
 	"github.com/spotlightpa/almanack/internal/services/aws"
 	"github.com/spotlightpa/almanack/internal/services/google"
@@ -23,20 +22,17 @@ import (
 
 func TestProcessGDocsDoc(t *testing.T) {
 	almlog.UseTestLogger(t)
-	dbhandle := createTestDB(t)
+	createTestDB(t)
 
 	ctx := t.Context()
 	testfile.Run(t, "testdata/gdoc*", func(t *testing.T, path string) {
 		t.Parallel()
-		svc := almsvc.Services{
-			DB:         dbhandle,
-			Queries:    dbhandle.Queries(),
-			ImageStore: aws.NewBlobStore("mem://"),
-			FileStore:  aws.NewBlobStore("mem://"),
-			Gsvc:       new(google.Service),
-			Client: &http.Client{
-				Transport: reqtest.Replay(path),
-			},
+		svc := newTestServices(t)
+		svc.ImageStore = aws.NewBlobStore("mem://")
+		svc.FileStore = aws.NewBlobStore("mem://")
+		svc.Gsvc = new(google.Service)
+		svc.Client = &http.Client{
+			Transport: reqtest.Replay(path),
 		}
 		if os.Getenv("RECORD") != "" {
 			svc.Client.Transport = reqtest.Caching(nil, path)
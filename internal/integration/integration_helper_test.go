@@ -9,10 +9,13 @@ import (
 	"github.com/carlmjohnson/be"
 	"github.com/carlmjohnson/requests"
 	"github.com/carlmjohnson/requests/reqtest"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/spotlightpa/almanack/internal/almapp"
 	"github.com/spotlightpa/almanack/internal/almsvc"
 	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/github"
+	"github.com/spotlightpa/almanack/internal/services/index"
 )
 
 var (
@@ -36,6 +39,55 @@ func createTestDB(t *testing.T) *db.Handle {
 	return db.NewHandle(pool)
 }
 
+// newTestServices returns Services backed by a test database,
+// a git repo in t.ArtifactDir(), and a mock indexer.
+func newTestServices(t *testing.T) almsvc.Services {
+	t.Helper()
+	dbhandle := createTestDB(t)
+	return almsvc.Services{
+		DB:           dbhandle,
+		Queries:      dbhandle.Queries(),
+		ContentStore: github.NewGitRepo(t.ArtifactDir()),
+		Indexer:      index.MockIndexer{},
+	}
+}
+
+// saveTestPage saves p, including its ExpireAt, publishing it if requested,
+// and returns the saved page.
+// SourceType and SourceID default to a manual page.
+func saveTestPage(t *testing.T, svc almsvc.Services, p db.Page, publish bool) db.Page {
+	t.Helper()
+	ctx := t.Context()
+	if p.SourceType == "" {
+		p.SourceType = "manual"
+		p.SourceID = "n/a"
+	}
+	expireAt := p.ExpireAt
+	err := svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		if txerr = p.Save(ctx, txq, false); txerr != nil {
+			return txerr
+		}
+		if expireAt.Valid {
+			if p, txerr = txq.UpdatePage(ctx, db.UpdatePageParams{
+				ID:          p.ID,
+				SetExpireAt: true,
+				ExpireAt:    expireAt,
+				ScheduleFor: db.NullTime,
+			}); txerr != nil {
+				return txerr
+			}
+		}
+		if publish {
+			var warning error
+			txerr, warning = svc.PublishPage(ctx, txq, &p)
+			be.NilErr(t, warning)
+		}
+		return txerr
+	})
+	be.NilErr(t, err)
+	return p
+}
+
 // newTestServer starts an httptest.Server backed by svc.
 // It registers cleanup and returns a *requests.Builder
 // pre-configured with the server's base URL and a mock Authorization header.
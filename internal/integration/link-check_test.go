@@ -0,0 +1,97 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestLinkCheck(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	create := func(path, upath, body string) db.Page {
+		return saveTestPage(t, svc, db.Page{
+			FilePath: path,
+			Frontmatter: db.Map{
+				"title": path,
+				"url":   upath,
+			},
+			Body: body,
+		}, true)
+	}
+	create("content/target.md", "/content/target/", "")
+	create("content/news/story.md", "/news/story/", "")
+	_, err := svc.Queries.UpsertRedirect(ctx, db.UpsertRedirectParams{
+		From:  "/old-target/",
+		To:    "/content/target/",
+		Code:  http.StatusMovedPermanently,
+		Roles: []string{},
+	})
+	be.NilErr(t, err)
+	_, err = svc.Queries.UpsertRedirect(ctx, db.UpsertRedirectParams{
+		From:  "/loop/",
+		To:    "/loop/",
+		Code:  http.StatusMovedPermanently,
+		Roles: []string{},
+	})
+	be.NilErr(t, err)
+
+	source := create("content/source.md", "/content/source/", `
+[good](/content/target)
+[moved](https://www.spotlightpa.org/old-target/)
+[missing](/content/missing/)
+[loop](/loop/)
+[home](/)
+[section](/news/)
+[more](/news/page/2/)
+[topic](/topics/budget/)
+[no section](/empty/)
+[ok](`+ts.URL+`/ok)
+[gone](`+ts.URL+`/gone)
+`)
+
+	be.NilErr(t, svc.CheckLinks(ctx, 100))
+
+	pages, err := svc.ListBrokenLinks(ctx)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(pages))
+	be.Equal(t, source.ID, pages[0].PageID)
+	// Lists that Hugo generates aren't reported
+	be.Equal(t, 3, len(pages[0].Internal))
+	be.Equal(t, "/content/missing/", pages[0].Internal[0].URL)
+	be.Equal(t, "/empty/", pages[0].Internal[1].URL)
+	be.Equal(t, "/loop/", pages[0].Internal[2].URL)
+	be.Equal(t, "too many redirects", pages[0].Internal[2].Error)
+	be.Equal(t, 1, len(pages[0].External))
+	be.Equal(t, ts.URL+"/gone", pages[0].External[0].URL)
+	be.Equal(t, 404, pages[0].External[0].Status)
+
+	// Nothing is due for a recheck
+	urls, err := svc.Queries.ListLinksToCheck(ctx, db.ListLinksToCheckParams{
+		CheckedBefore: source.CreatedAt,
+		Limit:         100,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 0, len(urls))
+
+	// Republishing drops links that were removed
+	source.Body = "[good](/content/target)"
+	source = saveTestPage(t, svc, source, true)
+	pages, err = svc.ListBrokenLinks(ctx)
+	be.NilErr(t, err)
+	be.Equal(t, 0, len(pages))
+}
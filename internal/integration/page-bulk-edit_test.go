@@ -0,0 +1,136 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestPageBulkEdit(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	create := func(path, byline string, publish bool) db.Page {
+		return saveTestPage(t, svc, db.Page{
+			FilePath: path,
+			Frontmatter: db.Map{
+				"title":     filepath.Base(path),
+				"slug":      filepath.Base(path),
+				"published": "2025-01-01T00:00:00Z",
+				"byline":    byline,
+				"authors":   []string{byline},
+			},
+		}, publish)
+	}
+	pub := create("content/news/a.md", "Jon Smtih", true)
+	draft := create("content/news/b.md", "Jon Smtih", false)
+	other := create("content/news/c.md", "Ann", true)
+	outside := create("content/berks/d.md", "Jon Smtih", true)
+
+	run := func(edit almsvc.BulkEdit) db.Job {
+		job, err := svc.CreateBulkEditJob(ctx, edit)
+		be.NilErr(t, err)
+		be.NilErr(t, svc.RunPendingJobs(ctx))
+		job, err = svc.Queries.GetJobByID(ctx, job.ID)
+		be.NilErr(t, err)
+		be.Equal(t, "", job.Error)
+		return job
+	}
+
+	// Bad requests are rejected up front
+	_, err := svc.CreateBulkEditJob(ctx, almsvc.BulkEdit{
+		Filter: almsvc.PageFilter{FilePath: "content/news/"},
+		Ops:    []db.FrontmatterOp{{Op: "move", Path: "/byline"}},
+	})
+	be.Nonzero(t, err)
+	_, err = svc.CreateBulkEditJob(ctx, almsvc.BulkEdit{
+		Filter: almsvc.PageFilter{FilePath: "data/"},
+		Ops:    []db.FrontmatterOp{{Op: "remove", Path: "/byline"}},
+	})
+	be.Nonzero(t, err)
+
+	edit := almsvc.BulkEdit{
+		Filter: almsvc.PageFilter{FilePath: "content/news/", Author: "Smtih"},
+		Ops: []db.FrontmatterOp{
+			{Op: "replace-text", Path: "/byline", Find: "Smtih", Value: "Smith"},
+			{Op: "replace-text", Path: "/authors", Find: "Smtih", Value: "Smith"},
+		},
+	}
+
+	// Dry run previews without saving
+	job := run(edit)
+	be.Equal(t, 2, job.Total)
+	be.Equal(t, false, job.Result["apply"].(bool))
+	be.Equal(t, 2, job.Result["changed"].(float64))
+	p, err := svc.Queries.GetPageByID(ctx, pub.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "Jon Smtih", p.Frontmatter["byline"].(string))
+
+	// Apply saves every match and republishes the published ones
+	edit.Apply = true
+	job = run(edit)
+	be.Equal(t, 2, job.Result["changed"].(float64))
+	be.Equal(t, 1, job.Result["published"].(float64))
+
+	p, err = svc.Queries.GetPageByID(ctx, pub.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "Jon Smith", p.Frontmatter["byline"].(string))
+	b, err := os.ReadFile(filepath.Join(t.ArtifactDir(), pub.FilePath))
+	be.NilErr(t, err)
+	be.In(t, "Jon Smith", string(b))
+	be.NotIn(t, "Smtih", string(b))
+
+	p, err = svc.Queries.GetPageByID(ctx, draft.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "Jon Smith", p.Frontmatter["byline"].(string))
+	be.False(t, p.LastPublished.Valid)
+	_, err = os.Stat(filepath.Join(t.ArtifactDir(), draft.FilePath))
+	be.True(t, os.IsNotExist(err))
+
+	p, err = svc.Queries.GetPageByID(ctx, outside.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "Jon Smtih", p.Frontmatter["byline"].(string))
+
+	// Pages that fail a test op are skipped
+	job = run(almsvc.BulkEdit{
+		Filter: almsvc.PageFilter{FilePath: "content/news/"},
+		Ops: []db.FrontmatterOp{
+			{Op: "test", Path: "/byline", Value: "Ann"},
+			{Op: "add", Path: "/image-credit", Value: "Spotlight PA"},
+		},
+		Apply: true,
+	})
+	be.Equal(t, 3, job.Total)
+	be.Equal(t, 1, job.Result["changed"].(float64))
+	p, err = svc.Queries.GetPageByID(ctx, other.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "Spotlight PA", p.Frontmatter["image-credit"].(string))
+	p, err = svc.Queries.GetPageByID(ctx, pub.ID)
+	be.NilErr(t, err)
+	_, ok := p.Frontmatter["image-credit"]
+	be.False(t, ok)
+
+	// Moving a published page keeps its old URL working
+	oldURL := other.URLPath.String
+	be.Equal(t, "/news/2024/12/c.md/", oldURL)
+	job = run(almsvc.BulkEdit{
+		Filter: almsvc.PageFilter{FilePath: "content/news/", Author: "Ann"},
+		Ops:    []db.FrontmatterOp{{Op: "replace", Path: "/slug", Value: "moved"}},
+		Apply:  true,
+	})
+	be.Equal(t, 1, job.Result["published"].(float64))
+	p, err = svc.Queries.GetPageByID(ctx, other.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "/news/2024/12/moved/", p.URLPath.String)
+	be.AllEqual(t, []string{oldURL}, p.Aliases())
+	redirect, err := svc.Queries.GetRedirect(ctx, oldURL)
+	be.NilErr(t, err)
+	be.Equal(t, "/news/2024/12/moved/", redirect.To)
+}
@@ -0,0 +1,147 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/algolia/algoliasearch-client-go/v3/algolia/search"
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/aws"
+	"github.com/spotlightpa/almanack/internal/services/index"
+)
+
+func TestPageCorrection(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.FileStore = aws.NewTestBlobStore(t.ArtifactDir(), "file")
+
+	page := db.Page{
+		FilePath: "content/news/corrected.md",
+		Frontmatter: db.Map{
+			"title":     "Corrected story",
+			"published": time.Now().Format(time.RFC3339),
+		},
+		Body: "Lorem ipsum.",
+	}
+	publish := func() {
+		t.Helper()
+		page = saveTestPage(t, svc, page, true)
+	}
+	publish()
+
+	// Bad corrections are rejected
+	_, err := svc.SavePageCorrection(ctx, db.PageCorrection{
+		PageID: page.ID,
+		Kind:   "retraction",
+	})
+	be.In(t, "kind must be one of", err.Error())
+
+	update, err := svc.SavePageCorrection(ctx, db.PageCorrection{
+		PageID: page.ID,
+		Kind:   "update",
+		Note:   "  Added reaction.  ",
+	})
+	be.NilErr(t, err)
+	be.Equal(t, "Added reaction.", update.Note)
+	correction, err := svc.SavePageCorrection(ctx, db.PageCorrection{
+		PageID:      page.ID,
+		Kind:        "correction",
+		Note:        "Fixed a name.",
+		CorrectedAt: update.CorrectedAt.Add(-time.Hour),
+	})
+	be.NilErr(t, err)
+
+	// Corrections aren't in the feed until the page is republished
+	be.NilErr(t, svc.UpdateCorrectionsFeed(ctx))
+	var feed struct {
+		Corrections []db.ListPublishedCorrectionsRow `json:"corrections"`
+	}
+	readFeed := func() {
+		b, err := os.ReadFile(filepath.Join(t.ArtifactDir(), "file", "feeds", "corrections.json"))
+		be.NilErr(t, err)
+		be.NilErr(t, json.Unmarshal(b, &feed))
+	}
+	readFeed()
+	be.Equal(t, 0, len(feed.Corrections))
+
+	publish()
+	content, err := svc.ContentStore.GetFile(ctx, page.FilePath)
+	be.NilErr(t, err)
+	be.Equal(t, 2, strings.Count(content, "[[corrections]]"))
+	// Sorted oldest first
+	be.True(t, strings.Index(content, "Fixed a name.") < strings.Index(content, "Added reaction."))
+	saved, err := svc.Queries.GetPageByID(ctx, page.ID)
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(saved.Frontmatter["corrections"].([]any)))
+
+	be.NilErr(t, svc.UpdateCorrectionsFeed(ctx))
+	readFeed()
+	be.Equal(t, 2, len(feed.Corrections))
+	be.Equal(t, "Added reaction.", feed.Corrections[0].Note)
+	be.Equal(t, "Corrected story", feed.Corrections[0].Title)
+
+	// Deleting every correction removes them from the page
+	for _, c := range []db.PageCorrection{update, correction} {
+		_, err = svc.Queries.DeletePageCorrection(ctx, c.ID)
+		be.NilErr(t, err)
+	}
+	publish()
+	content, err = svc.ContentStore.GetFile(ctx, page.FilePath)
+	be.NilErr(t, err)
+	be.False(t, strings.Contains(content, "corrections"))
+}
+
+// indexRecorder is an index.Indexer that keeps the objects it saves.
+type indexRecorder struct {
+	index.MockIndexer
+	saved *[]any
+}
+
+func (ir indexRecorder) SaveObject(object any, opts ...any) (search.SaveObjectRes, error) {
+	*ir.saved = append(*ir.saved, object)
+	return ir.MockIndexer.SaveObject(object, opts...)
+}
+
+// Run with -race: publishing renders corrections and aliases into the frontmatter,
+// so the page must not be indexed until that is done.
+func TestPublishPageIndexesCorrectedPage(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	var saved []any
+	svc.Indexer = indexRecorder{saved: &saved}
+
+	page := saveTestPage(t, svc, db.Page{
+		FilePath:    "content/pages/indexed-correction.md",
+		Frontmatter: db.Map{"title": "Indexed", "slug": "indexed"},
+	}, true)
+	_, err := svc.SavePageCorrection(ctx, db.PageCorrection{
+		PageID: page.ID,
+		Kind:   "correction",
+		Note:   "Fixed a date.",
+	})
+	be.NilErr(t, err)
+
+	page.Frontmatter["slug"] = "indexed-moved"
+	page = saveTestPage(t, svc, page, true)
+	be.Equal(t, 2, len(saved))
+	b, err := json.Marshal(saved[1])
+	be.NilErr(t, err)
+	var indexed struct {
+		URL     string   `json:"URL"`
+		Aliases []string `json:"aliases"`
+	}
+	be.NilErr(t, json.Unmarshal(b, &indexed))
+	be.Equal(t, "https://www.spotlightpa.org/pages/indexed-moved/", indexed.URL)
+	be.AllEqual(t, []string{"/pages/indexed/"}, indexed.Aliases)
+	be.Equal(t, 1, len(page.Frontmatter["corrections"].([]any)))
+}
@@ -0,0 +1,108 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/earthboundkid/slackhook/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestPopExpiredPages(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.SlackSocial = slackhook.New(slackhook.MockClient)
+	tmp := t.ArtifactDir()
+
+	publish := func(path, slug string, expireAt time.Time) db.Page {
+		t.Helper()
+		return saveTestPage(t, svc, db.Page{
+			FilePath:    path,
+			Frontmatter: db.Map{"title": slug, "slug": slug},
+			ExpireAt:    pgtype.Timestamptz{Time: expireAt, Valid: true},
+		}, true)
+	}
+	expired := publish("content/pages/expired.md", "expired", time.Now().Add(-time.Minute))
+	kept := publish("content/pages/kept.md", "kept", time.Now().Add(time.Hour))
+
+	// Put both pages on the homepage and sidebar, now and in the future
+	for _, when := range []time.Time{time.Now().Add(-time.Hour), time.Now().Add(time.Hour)} {
+		_, err := svc.UpdateSiteConfig(ctx, almsvc.HomepageLoc, "", []almsvc.ScheduledSiteConfig{{
+			ScheduleFor: when,
+			Data: db.Map{
+				"featuredStories": []any{expired.FilePath, kept.FilePath},
+			},
+		}})
+		be.NilErr(t, err)
+		_, err = svc.UpdateSiteConfig(ctx, almsvc.SidebarLoc, "", []almsvc.ScheduledSiteConfig{{
+			ScheduleFor: when,
+			Data: db.Map{
+				"items": []any{
+					map[string]any{"page": expired.FilePath},
+					map[string]any{"page": kept.FilePath},
+				},
+			},
+		}})
+		be.NilErr(t, err)
+	}
+	_, err := svc.Queries.PopScheduledSiteChanges(ctx, almsvc.HomepageLoc)
+	be.NilErr(t, err)
+	_, err = svc.Queries.PopScheduledSiteChanges(ctx, almsvc.SidebarLoc)
+	be.NilErr(t, err)
+
+	err, warning := svc.PopExpiredPages(ctx)
+	be.NilErr(t, err)
+	be.NilErr(t, warning)
+
+	// The expired page is unpublished and deleted
+	p, err := svc.Queries.GetPageByID(ctx, expired.ID)
+	be.NilErr(t, err)
+	be.False(t, p.LastPublished.Valid)
+	be.False(t, p.ExpireAt.Valid)
+	_, err = os.Stat(filepath.Join(tmp, expired.FilePath))
+	be.True(t, os.IsNotExist(err))
+
+	// The other page is untouched
+	p, err = svc.Queries.GetPageByID(ctx, kept.ID)
+	be.NilErr(t, err)
+	be.True(t, p.LastPublished.Valid)
+	be.True(t, p.ExpireAt.Valid)
+	_, err = os.Stat(filepath.Join(tmp, kept.FilePath))
+	be.NilErr(t, err)
+
+	// Site data no longer refers to the expired page
+	configs, err := svc.Queries.GetSiteData(ctx, almsvc.HomepageLoc)
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(configs))
+	for _, config := range configs {
+		b, err := json.Marshal(config.Data)
+		be.NilErr(t, err)
+		be.Equal(t, `{"featuredStories":["content/pages/kept.md"]}`, string(b))
+	}
+	configs, err = svc.Queries.GetSiteData(ctx, almsvc.SidebarLoc)
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(configs))
+	for _, config := range configs {
+		b, err := json.Marshal(config.Data)
+		be.NilErr(t, err)
+		be.Equal(t, `{"items":[{"page":"content/pages/kept.md"}]}`, string(b))
+	}
+	b, err := os.ReadFile(filepath.Join(tmp, almsvc.HomepageLoc))
+	be.NilErr(t, err)
+	be.In(t, "kept.md", string(b))
+	be.NotIn(t, "expired.md", string(b))
+
+	// Running again is a no-op
+	err, warning = svc.PopExpiredPages(ctx)
+	be.NilErr(t, err)
+	be.NilErr(t, warning)
+}
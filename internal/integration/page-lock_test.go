@@ -0,0 +1,58 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/netlifyid"
+)
+
+func TestPageLocks(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+	svc := newTestServices(t)
+
+	p := saveTestPage(t, svc, db.Page{FilePath: "content/news/locked.md"}, false)
+
+	user := func(email, name string) *netlifyid.JWT {
+		var jwt netlifyid.JWT
+		jwt.User.Email = email
+		jwt.User.UserMetadata.FullName = name
+		return &jwt
+	}
+	alice := netlifyid.NewContext(ctx, user("alice@example.com", "Alice"))
+	bob := netlifyid.NewContext(ctx, user("bob@example.com", ""))
+
+	editors, err := svc.HeartbeatPageLock(alice, p.ID)
+	be.NilErr(t, err)
+	be.Zero(t, len(editors))
+
+	editors, err = svc.HeartbeatPageLock(bob, p.ID)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(editors))
+	be.Equal(t, "Alice", editors[0].Name)
+
+	editors, err = svc.ListPageEditors(alice, p.ID)
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(editors))
+	be.Equal(t, "bob@example.com", editors[0].Email)
+
+	// Anonymous users see everyone but aren't recorded
+	editors, err = svc.HeartbeatPageLock(ctx, p.ID)
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(editors))
+
+	be.NilErr(t, svc.ReleasePageLock(bob, p.ID))
+	editors, err = svc.ListPageEditors(alice, p.ID)
+	be.NilErr(t, err)
+	be.Zero(t, len(editors))
+
+	// Stale locks are ignored and cleaned up
+	n, err := svc.Queries.DeleteStalePageLocks(ctx, time.Now().Add(time.Minute))
+	be.NilErr(t, err)
+	be.Equal(t, 1, n)
+	be.NilErr(t, svc.CleanPageLocks(ctx))
+}
@@ -0,0 +1,83 @@
+package integration_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestPageRelated(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	now := time.Now().UTC()
+	create := func(path, title string, topics []string, published time.Time, publish bool) db.Page {
+		return saveTestPage(t, svc, db.Page{
+			FilePath: path,
+			Frontmatter: db.Map{
+				"title":     title,
+				"published": published.Format(time.RFC3339),
+				"topics":    topics,
+			},
+			Body: "Lorem ipsum.",
+		}, publish)
+	}
+	source := create("content/news/source.md",
+		"Lawmakers pass state budget", []string{"Budget"}, now, false)
+	both := create("content/news/both.md",
+		"Senate budget vote delayed", []string{"Budget"}, now.AddDate(0, 0, -2), true)
+	text := create("content/news/text.md",
+		"What the budget means for schools", []string{"Education"}, now.AddDate(0, 0, -3), true)
+	topic := create("content/news/topic.md",
+		"Governor signs bill", []string{"Budget"}, now.AddDate(-1, 0, 0), true)
+	create("content/news/draft.md",
+		"Budget draft", []string{"Budget"}, now, false)
+	create("content/news/unrelated.md",
+		"Flooding closes roads", []string{"Weather"}, now, true)
+	create("content/videos/video.md",
+		"Budget explained", []string{"Budget"}, now, true)
+
+	pages, computedAt, err := svc.ListRelatedPages(ctx, source.ID, false)
+	be.NilErr(t, err)
+	be.False(t, computedAt.IsZero())
+	ids := make([]int64, 0, len(pages))
+	for _, p := range pages {
+		ids = append(ids, p.ID)
+	}
+	// Shared text and topic beats either one,
+	// and unpublished, unrelated, and non-news pages are left out
+	be.AllEqual(t, []int64{both.ID, text.ID, topic.ID}, ids)
+	be.Equal(t, "Senate budget vote delayed", pages[0].Title)
+	be.Equal(t, "news/both.md", pages[0].ContentPath)
+
+	// The suggestions were saved
+	rows, err := svc.Queries.ListRelatedPages(ctx, source.ID)
+	be.NilErr(t, err)
+	be.Equal(t, 3, len(rows))
+
+	// The nightly job computes suggestions for recent pages
+	job, err := svc.CreateJob(ctx, almsvc.JobPageRelated, struct{}{})
+	be.NilErr(t, err)
+	be.NilErr(t, svc.RunPendingJobs(ctx))
+	job, err = svc.Queries.GetJobByID(ctx, job.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "", job.Error)
+	// Every news page but the one from a year ago
+	be.Equal(t, 5, job.Total)
+	rows, err = svc.Queries.ListRelatedPages(ctx, both.ID)
+	be.NilErr(t, err)
+	be.Nonzero(t, len(rows))
+
+	// Only one nightly job is queued
+	_, err = svc.Queries.CreateJobUnlessRecent(ctx, db.CreateJobUnlessRecentParams{
+		Kind:         almsvc.JobPageRelated,
+		CreatedSince: now.Add(-time.Hour),
+	})
+	be.True(t, db.IsNotFound(err))
+}
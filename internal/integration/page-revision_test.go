@@ -0,0 +1,110 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestPageRevisions(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	tmp := t.ArtifactDir()
+
+	const path = "content/news/revisions.md"
+	p := saveTestPage(t, svc, db.Page{
+		FilePath:    path,
+		Frontmatter: db.Map{"title": "First"},
+		Body:        "one",
+	}, false)
+
+	update := func(title, body string) {
+		t.Helper()
+		_, err := svc.Queries.UpdatePageWithRevision(ctx, db.UpdatePageParams{
+			ID:             p.ID,
+			SetFrontmatter: true,
+			Frontmatter:    db.Map{"title": title},
+			SetBody:        true,
+			Body:           body,
+			ScheduleFor:    db.NullTime,
+		})
+		be.NilErr(t, err)
+	}
+	update("Second", "two")
+	// No-op updates don't create a revision
+	update("Second", "two")
+
+	revs, err := svc.Queries.ListPageRevisions(ctx, db.ListPageRevisionsParams{
+		PageID: p.ID,
+		Limit:  10,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(revs))
+	be.Equal(t, "two", revs[0].Body)
+	be.Equal(t, "one", revs[1].Body)
+
+	diff := db.DiffPageRevisions(&revs[1], &revs[0])
+	be.True(t, diff.BodyChanged)
+	be.Equal(t, 1, len(diff.Frontmatter))
+	be.Equal(t, "title", diff.Frontmatter[0].Key)
+
+	// Unpublished pages are restored without publishing
+	restored, err, warning := svc.RestorePageRevision(ctx, revs[1].ID)
+	be.NilErr(t, err)
+	be.NilErr(t, warning)
+	be.Equal(t, "one", restored.Body)
+	be.False(t, restored.LastPublished.Valid)
+	_, err = os.Stat(filepath.Join(tmp, path))
+	be.ErrorIs(t, os.ErrNotExist, err)
+
+	revs, err = svc.Queries.ListPageRevisions(ctx, db.ListPageRevisionsParams{
+		PageID: p.ID,
+		Limit:  10,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 3, len(revs))
+
+	_, err, _ = svc.RestorePageRevision(ctx, -1)
+	be.Nonzero(t, err)
+}
+
+func TestPageRevisionsOnCreateAndSchedule(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+	svc := newTestServices(t)
+
+	p := saveTestPage(t, svc, db.Page{
+		FilePath:    "content/pages/revisions-scheduled.md",
+		Frontmatter: db.Map{"title": "Scheduled"},
+		Body:        "body",
+		ScheduleFor: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	}, false)
+
+	revs, err := svc.Queries.ListPageRevisions(ctx, db.ListPageRevisionsParams{
+		PageID: p.ID,
+		Limit:  10,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(revs))
+	be.Equal(t, "body", revs[0].Body)
+	be.False(t, revs[0].LastPublished.Valid)
+
+	// Publishing on schedule is recorded too
+	err, _ = svc.PopScheduledPages(ctx)
+	be.NilErr(t, err)
+	revs, err = svc.Queries.ListPageRevisions(ctx, db.ListPageRevisionsParams{
+		PageID: p.ID,
+		Limit:  10,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(revs))
+	be.True(t, revs[0].LastPublished.Valid)
+}
@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestSearchPages(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	const section = "content/searchtest/"
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 55 {
+		page := db.Page{
+			FilePath: fmt.Sprintf("%spage-%02d.md", section, i),
+			Frontmatter: db.Map{
+				"title":     fmt.Sprintf("Search test %d", i),
+				"published": start.Add(time.Duration(i) * time.Hour).Format(time.RFC3339),
+				"authors":   []any{"Pat Searcher"},
+			},
+			Body: "Lorem ipsum.",
+		}
+		if i%5 == 0 {
+			page.Frontmatter["authors"] = []any{"Jo Zephyrquux"}
+			page.Frontmatter["topics"] = []any{"Search Testing"}
+			page.Body = "The zephyrquux report."
+		}
+		if i == 1 {
+			page.ScheduleFor = pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true}
+		}
+		saveTestPage(t, svc, page, i == 0)
+	}
+
+	// Bad filters are rejected
+	_, err := svc.SearchPages(ctx, almsvc.PageSearch{Section: "content/%"})
+	be.Nonzero(t, err)
+	_, err = svc.SearchPages(ctx, almsvc.PageSearch{State: "deleted"})
+	be.Nonzero(t, err)
+	_, err = svc.SearchPages(ctx, almsvc.PageSearch{Cursor: "nope"})
+	be.Nonzero(t, err)
+
+	// Results page through with a cursor, newest first
+	res, err := svc.SearchPages(ctx, almsvc.PageSearch{Section: section})
+	be.NilErr(t, err)
+	be.Equal(t, 50, len(res.Pages))
+	be.Equal(t, section+"page-54.md", res.Pages[0].FilePath)
+	be.Nonzero(t, res.NextCursor)
+	be.True(t, slices.Contains(res.Facets["author"], almsvc.PageFacet{Value: "Jo Zephyrquux", Count: 11}))
+	be.True(t, slices.Contains(res.Facets["state"], almsvc.PageFacet{Value: "published", Count: 1}))
+	be.True(t, slices.Contains(res.Facets["state"], almsvc.PageFacet{Value: "scheduled", Count: 1}))
+	be.True(t, slices.Contains(res.Facets["state"], almsvc.PageFacet{Value: "draft", Count: 53}))
+
+	res, err = svc.SearchPages(ctx, almsvc.PageSearch{
+		Section: section,
+		Cursor:  res.NextCursor,
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 5, len(res.Pages))
+	be.Equal(t, section+"page-04.md", res.Pages[0].FilePath)
+	be.Zero(t, res.NextCursor)
+	be.Zero(t, len(res.Facets))
+
+	// Filters combine
+	res, err = svc.SearchPages(ctx, almsvc.PageSearch{
+		Query:          "zephyrquux",
+		Section:        section,
+		Topic:          "Search Testing",
+		PublishedAfter: start.Add(10 * time.Hour),
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 9, len(res.Pages))
+	for _, page := range res.Pages {
+		be.Equal(t, "draft", page.State)
+	}
+
+	// Facets ignore their own filter, so other values stay visible
+	res, err = svc.SearchPages(ctx, almsvc.PageSearch{
+		Section: section,
+		Author:  "Pat Searcher",
+		State:   "scheduled",
+	})
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(res.Pages))
+	be.Equal(t, section+"page-01.md", res.Pages[0].FilePath)
+	be.AllEqual(t, []almsvc.PageFacet{{Value: "Pat Searcher", Count: 1}}, res.Facets["author"])
+	be.True(t, slices.Contains(res.Facets["state"], almsvc.PageFacet{Value: "draft", Count: 43}))
+}
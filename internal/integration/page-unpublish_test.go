@@ -0,0 +1,67 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/earthboundkid/slackhook/v2"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/services/github"
+)
+
+func TestUnpublishPage(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+	svc.SlackSocial = slackhook.New(slackhook.MockClient)
+	tmp := t.ArtifactDir()
+	repo := svc.ContentStore.(*github.GitRepo)
+
+	const path = "content/news/unpublish.md"
+	p := saveTestPage(t, svc, db.Page{FilePath: path}, false)
+
+	// Can't unpublish a draft
+	_, err, _ := svc.UnpublishPage(ctx, p.ID, "")
+	be.Nonzero(t, err)
+
+	p.Frontmatter = db.Map{"title": "Going away", "slug": "going-away"}
+	p.Body = "bye"
+	p = saveTestPage(t, svc, p, true)
+	be.True(t, p.LastPublished.Valid)
+	_, err = os.Stat(filepath.Join(tmp, path))
+	be.NilErr(t, err)
+
+	unpublished, err, warning := svc.UnpublishPage(ctx, p.ID, "/news/")
+	be.NilErr(t, err)
+	be.NilErr(t, warning)
+	be.False(t, unpublished.LastPublished.Valid)
+	be.False(t, unpublished.ScheduleFor.Valid)
+	be.Equal(t, "bye", unpublished.Body)
+
+	_, err = os.Stat(filepath.Join(tmp, path))
+	be.ErrorIs(t, os.ErrNotExist, err)
+
+	commits, err := repo.Log(ctx, path)
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(commits))
+	be.In(t, "unpublishing", commits[0].Subject)
+	be.In(t, "publishing", commits[1].Subject)
+	be.Equal(t, "Almanack", commits[1].AuthorName)
+
+	redirect, err := svc.Queries.GetRedirect(ctx, p.URLPath.String)
+	be.NilErr(t, err)
+	be.Equal(t, "/news/", redirect.To)
+	be.Equal(t, 301, redirect.Code)
+
+	revs, err := svc.Queries.ListPageRevisions(ctx, db.ListPageRevisionsParams{
+		PageID: p.ID,
+		Limit:  10,
+	})
+	be.NilErr(t, err)
+	be.False(t, revs[0].LastPublished.Valid)
+	be.True(t, revs[1].LastPublished.Valid)
+}
@@ -0,0 +1,88 @@
+package integration_test
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/jackc/pgx/v5"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/db"
+)
+
+func TestPublishMovedPage(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	publish := func(path, slug string) db.Page {
+		t.Helper()
+		return saveTestPage(t, svc, db.Page{
+			FilePath:    path,
+			Frontmatter: db.Map{"title": slug, "slug": slug},
+		}, true)
+	}
+	republish := func(p *db.Page) (warning error) {
+		t.Helper()
+		err := svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+			txerr, warning = svc.PublishPage(ctx, txq, p)
+			return txerr
+		})
+		be.NilErr(t, err)
+		return warning
+	}
+	p := publish("content/pages/first.md", "first")
+	be.Equal(t, "/pages/first/", p.URLPath.String)
+	other := publish("content/pages/other.md", "other")
+	be.Equal(t, "/pages/other/", other.URLPath.String)
+
+	// Changing the slug moves the page
+	p.Frontmatter["slug"] = "second"
+	be.NilErr(t, republish(&p))
+	be.Equal(t, "/pages/second/", p.URLPath.String)
+	be.AllEqual(t, []string{"/pages/first/"}, p.Aliases())
+	redirect, err := svc.Queries.GetRedirect(ctx, "/pages/first/")
+	be.NilErr(t, err)
+	be.Equal(t, "/pages/second/", redirect.To)
+
+	// Republishing in place adds nothing
+	be.NilErr(t, republish(&p))
+	be.AllEqual(t, []string{"/pages/first/"}, p.Aliases())
+
+	// Moving onto another page's URL warns
+	p.Frontmatter["slug"] = "other"
+	be.Nonzero(t, republish(&p))
+	be.AllEqual(t, []string{"/pages/first/", "/pages/second/"}, p.Aliases())
+
+	// Moving back removes the alias and redirect loop
+	p.Frontmatter["slug"] = "first"
+	be.NilErr(t, republish(&p))
+	be.Equal(t, "/pages/first/", p.URLPath.String)
+	be.AllEqual(t, []string{"/pages/second/", "/pages/other/"}, p.Aliases())
+	_, err = svc.Queries.GetRedirect(ctx, "/pages/first/")
+	be.True(t, db.IsNotFound(err))
+
+	// JSON pages move the same way
+	video := publish("content/videos/clip.md", "clip")
+	be.Equal(t, "/videos/clip/", video.URLPath.String)
+	err = svc.DB.Tx(ctx, pgx.TxOptions{}, func(txq *db.Queries) (txerr error) {
+		var published *db.Page
+		published, txerr, _ = svc.PublishJSONPage(ctx, txq, db.UpdatePageParams{
+			ID:               video.ID,
+			SetFrontmatter:   true,
+			Frontmatter:      db.Map{"title": "clip", "slug": "renamed-clip"},
+			SetLastPublished: true,
+			ScheduleFor:      db.NullTime,
+		})
+		if txerr == nil {
+			video = *published
+		}
+		return txerr
+	})
+	be.NilErr(t, err)
+	be.Equal(t, "/videos/renamed-clip/", video.URLPath.String)
+	be.AllEqual(t, []string{"/videos/clip/"}, video.Aliases())
+	redirect, err = svc.Queries.GetRedirect(ctx, "/videos/clip/")
+	be.NilErr(t, err)
+	be.Equal(t, "/videos/renamed-clip/", redirect.To)
+}
@@ -287,8 +287,11 @@ func TestServicePopScheduledPages(t *testing.T) {
 
 		p, err = svc.Queries.UpdatePage(ctx, db.UpdatePageParams{
 			ID:             p.ID,
-			SetFrontmatter: false,
-			Frontmatter:    map[string]any{},
+			SetFrontmatter: true,
+			Frontmatter: map[string]any{
+				"title":     "Pop",
+				"published": time.Now().AddDate(0, 0, -1),
+			},
 			SetBody:        false,
 			Body:           "",
 			SetScheduleFor: true,
@@ -313,4 +316,38 @@ func TestServicePopScheduledPages(t *testing.T) {
 		_, err = os.Stat(filepath.Join(tmp, path))
 		be.NilErr(t, err)
 	}
+	{
+		// Invalid pages go back to draft
+		const path = "content/news/test-pop-invalid.md"
+		p, err := svc.Queries.CreatePage(ctx, db.CreatePageParams{
+			FilePath:   path,
+			SourceType: "manual",
+			SourceID:   "n/a",
+		})
+		be.NilErr(t, err)
+
+		p, err = svc.Queries.UpdatePage(ctx, db.UpdatePageParams{
+			ID:             p.ID,
+			SetFrontmatter: true,
+			Frontmatter:    map[string]any{"title": 1},
+			SetScheduleFor: true,
+			ScheduleFor: pgtype.Timestamptz{
+				Time:  time.Now().AddDate(0, 0, -1),
+				Valid: true,
+			},
+		})
+		be.NilErr(t, err)
+
+		err, warning := svc.PopScheduledPages(ctx)
+		be.NilErr(t, err)
+		be.Nonzero(t, warning)
+
+		p, err = svc.Queries.GetPageByFilePath(ctx, path)
+		be.NilErr(t, err)
+		be.False(t, p.LastPublished.Valid)
+		be.False(t, p.ScheduleFor.Valid)
+
+		_, err = os.Stat(filepath.Join(tmp, path))
+		be.ErrorIs(t, os.ErrNotExist, err)
+	}
 }
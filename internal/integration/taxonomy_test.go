@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/almsvc"
+	"github.com/spotlightpa/almanack/internal/db"
+	"github.com/spotlightpa/almanack/internal/utils/stringx"
+)
+
+func TestTaxonomyJobs(t *testing.T) {
+	ctx := t.Context()
+	almlog.UseTestLogger(t)
+
+	svc := newTestServices(t)
+
+	create := func(path string, topics []string, publish bool) db.Page {
+		return saveTestPage(t, svc, db.Page{
+			FilePath: path,
+			Frontmatter: db.Map{
+				"title":     filepath.Base(path),
+				"slug":      filepath.Base(path),
+				"published": "2025-01-01T00:00:00Z",
+				"topics":    topics,
+			},
+		}, publish)
+	}
+	pub := create("content/news/a.md", []string{"Pensylvania", "Politics"}, true)
+	draft := create("content/news/b.md", []string{"Pensylvania"}, false)
+	other := create("content/news/c.md", []string{"Politics"}, true)
+
+	// Publishing created the landing page for the misspelled topic
+	oldTopic, err := svc.Queries.GetPageByFilePath(ctx, "content/topics/Pensylvania/_index.md")
+	be.NilErr(t, err)
+	be.True(t, oldTopic.LastPublished.Valid)
+
+	// Bad requests are rejected up front
+	_, err = svc.CreateTaxonomyJob(ctx, almsvc.TaxonomyChange{
+		Taxonomy: "tags", From: []string{"Pensylvania"}, To: "Pennsylvania",
+	}, false)
+	be.Nonzero(t, err)
+	_, err = svc.CreateTaxonomyJob(ctx, almsvc.TaxonomyChange{
+		Taxonomy: "topics", From: []string{"Pensylvania"}, To: "Politics",
+	}, false)
+	be.Nonzero(t, err)
+
+	// Rename
+	job, err := svc.CreateTaxonomyJob(ctx, almsvc.TaxonomyChange{
+		Taxonomy: "topics", From: []string{"Pensylvania"}, To: "Pennsylvania",
+	}, false)
+	be.NilErr(t, err)
+	be.False(t, job.StartedAt.Valid)
+	be.NilErr(t, svc.RunPendingJobs(ctx))
+
+	job, err = svc.Queries.GetJobByID(ctx, job.ID)
+	be.NilErr(t, err)
+	be.True(t, job.FinishedAt.Valid)
+	be.Equal(t, "", job.Error)
+	be.Equal(t, 2, job.Total)
+	be.Equal(t, 2, job.Processed)
+
+	p, err := svc.Queries.GetPageByID(ctx, pub.ID)
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{"Pennsylvania", "Politics"}, p.Topics())
+	b, err := os.ReadFile(filepath.Join(t.ArtifactDir(), pub.FilePath))
+	be.NilErr(t, err)
+	be.In(t, "Pennsylvania", string(b))
+
+	p, err = svc.Queries.GetPageByID(ctx, draft.ID)
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{"Pennsylvania"}, p.Topics())
+	be.False(t, p.LastPublished.Valid)
+
+	// The landing page moved and redirects
+	newTopic, err := svc.Queries.GetPageByFilePath(ctx, "content/topics/Pennsylvania/_index.md")
+	be.NilErr(t, err)
+	be.True(t, newTopic.LastPublished.Valid)
+	be.Equal(t, "Pennsylvania", newTopic.Frontmatter["title"].(string))
+	be.Equal(t, stringx.SlugifyURL("Pennsylvania"), newTopic.Frontmatter["slug"].(string))
+	be.AllEqual(t, []string{oldTopic.URLPath.String}, newTopic.Aliases())
+	oldTopic, err = svc.Queries.GetPageByID(ctx, oldTopic.ID)
+	be.NilErr(t, err)
+	be.False(t, oldTopic.LastPublished.Valid)
+	_, err = os.Stat(filepath.Join(t.ArtifactDir(), oldTopic.FilePath))
+	be.True(t, os.IsNotExist(err))
+	redirect, err := svc.Queries.GetRedirect(ctx, oldTopic.URLPath.String)
+	be.NilErr(t, err)
+	be.Equal(t, newTopic.URLPath.String, redirect.To)
+
+	// Merge into an existing topic
+	job, err = svc.CreateTaxonomyJob(ctx, almsvc.TaxonomyChange{
+		Taxonomy: "topics", From: []string{"Pennsylvania"}, To: "Politics",
+	}, true)
+	be.NilErr(t, err)
+	be.NilErr(t, svc.RunPendingJobs(ctx))
+	job, err = svc.Queries.GetJobByID(ctx, job.ID)
+	be.NilErr(t, err)
+	be.Equal(t, "", job.Error)
+
+	p, err = svc.Queries.GetPageByID(ctx, pub.ID)
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{"Politics"}, p.Topics())
+	p, err = svc.Queries.GetPageByID(ctx, other.ID)
+	be.NilErr(t, err)
+	be.AllEqual(t, []string{"Politics"}, p.Topics())
+
+	politics, err := svc.Queries.GetPageByFilePath(ctx, "content/topics/Politics/_index.md")
+	be.NilErr(t, err)
+	newTopic, err = svc.Queries.GetPageByID(ctx, newTopic.ID)
+	be.NilErr(t, err)
+	be.False(t, newTopic.LastPublished.Valid)
+	redirect, err = svc.Queries.GetRedirect(ctx, newTopic.URLPath.String)
+	be.NilErr(t, err)
+	be.Equal(t, politics.URLPath.String, redirect.To)
+}
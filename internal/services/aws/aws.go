@@ -119,10 +119,18 @@ func (bs BlobStore) BuildURL(srcPath string) string {
 }
 
 func (bs BlobStore) WriteFile(ctx context.Context, path string, h http.Header, data []byte) (err error) {
+	_, err = bs.WriteFileIfChanged(ctx, path, h, data)
+	return err
+}
+
+// WriteFileIfChanged is WriteFile,
+// but it also reports whether the file was written
+// or skipped because the same data and headers were already uploaded.
+func (bs BlobStore) WriteFileIfChanged(ctx context.Context, path string, h http.Header, data []byte) (written bool, err error) {
 	l := almlog.FromContext(ctx)
 	b, err := blob.OpenBucket(ctx, bs.bucket)
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer errorx.Defer(&err, b.Close)
 
@@ -140,17 +148,30 @@ func (bs BlobStore) WriteFile(ctx context.Context, path string, h http.Header, d
 		if string(checksum) == string(attrs.MD5) {
 			l.InfoContext(ctx, "aws.WriteFile: skipping; already uploaded",
 				"bucket", bs.bucket, "path", path)
-			return nil
+			return false, nil
 		}
 	}
 
 	l.InfoContext(ctx, "aws.WriteFile: writing", "bucket", bs.bucket, "path", path)
-	return b.WriteAll(ctx, path, data, &blob.WriterOptions{
+	err = b.WriteAll(ctx, path, data, &blob.WriterOptions{
 		CacheControl:       h.Get("Cache-Control"),
 		ContentType:        h.Get("Content-Type"),
 		ContentDisposition: h.Get("Content-Disposition"),
 		ContentMD5:         checksum,
 	})
+	return err == nil, err
+}
+
+func (bs BlobStore) ReadFile(ctx context.Context, path string) (data []byte, err error) {
+	l := almlog.FromContext(ctx)
+	b, err := blob.OpenBucket(ctx, bs.bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer errorx.Defer(&err, b.Close)
+
+	l.InfoContext(ctx, "aws.ReadFile", "bucket", bs.bucket, "path", path)
+	return b.ReadAll(ctx, path)
 }
 
 func (bs BlobStore) ReadMD5(ctx context.Context, path string) (hash []byte, size int64, err error) {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -18,7 +19,17 @@ import (
 	"google.golang.org/api/googleapi"
 )
 
+// Request fetches a document as it would look with all pending suggestions rejected.
 func Request(ctx context.Context, cl *http.Client, docID string) (d *docs.Document, err error) {
+	return request(ctx, cl, docID, "PREVIEW_WITHOUT_SUGGESTIONS")
+}
+
+// RequestWithSuggestions fetches a document with its pending suggestions marked inline.
+func RequestWithSuggestions(ctx context.Context, cl *http.Client, docID string) (d *docs.Document, err error) {
+	return request(ctx, cl, docID, "SUGGESTIONS_INLINE")
+}
+
+func request(ctx context.Context, cl *http.Client, docID, suggestionsViewMode string) (d *docs.Document, err error) {
 	var doc docs.Document
 	type errorReply struct {
 		Error googleapi.Error `json:"error"`
@@ -27,7 +38,7 @@ func Request(ctx context.Context, cl *http.Client, docID string) (d *docs.Docume
 	if err = requests.
 		URL("https://docs.googleapis.com").
 		Pathf("/v1/documents/%s", docID).
-		Param("suggestionsViewMode", "PREVIEW_WITHOUT_SUGGESTIONS").
+		Param("suggestionsViewMode", suggestionsViewMode).
 		Client(cl).
 		ErrorJSON(&errJSON).
 		ToJSON(&doc).
@@ -72,12 +83,50 @@ func Convert(doc *docs.Document) (n *html.Node) {
 	}
 	listInfo := buildListInfo(doc.Lists)
 	objectInfo := buildObjectInfo(doc.InlineObjects)
+	notes := &footnoteInfo{}
 	for _, el := range doc.Body.Content {
-		convertEl(n, el, listInfo, objectInfo)
+		convertEl(n, el, listInfo, objectInfo, notes)
+	}
+	if section := notes.section(doc.Footnotes, listInfo, objectInfo); section != nil {
+		n.AppendChild(section)
 	}
 	return
 }
 
+// footnoteInfo numbers footnotes in the order they are referenced.
+type footnoteInfo struct {
+	ids []string
+}
+
+func (info *footnoteInfo) add(id string) int {
+	if i := slices.Index(info.ids, id); i != -1 {
+		return i + 1
+	}
+	info.ids = append(info.ids, id)
+	return len(info.ids)
+}
+
+// section returns the notes as an ordered list
+// in a <section role="doc-endnotes"> or nil if there are none.
+func (info *footnoteInfo) section(footnotes map[string]docs.Footnote, listInfo map[string][]listLevel, objInfo map[string]*html.Node) *html.Node {
+	if len(info.ids) == 0 {
+		return nil
+	}
+	section := xhtml.New("section", "role", "doc-endnotes")
+	ol := xhtml.New("ol")
+	section.AppendChild(ol)
+	// Footnotes can't contain footnotes, but be safe
+	noNotes := &footnoteInfo{}
+	for i, id := range info.ids {
+		li := xhtml.New("li", "id", fmt.Sprintf("fn-%d", i+1))
+		for _, el := range footnotes[id].Content {
+			convertEl(li, el, listInfo, objInfo, noNotes)
+		}
+		ol.AppendChild(li)
+	}
+	return section
+}
+
 var tagForNamedStyle = map[string]string{
 	"NAMED_STYLE_TYPE_UNSPECIFIED": "div",
 	"NORMAL_TEXT":                  "p",
@@ -91,22 +140,57 @@ var tagForNamedStyle = map[string]string{
 	"HEADING_6":                    "h6",
 }
 
-func buildListInfo(lists map[string]docs.List) map[string]string {
-	m := map[string]string{}
+// listLevel is how a list is drawn at one level of nesting.
+type listLevel struct {
+	tag   string
+	start int64
+}
+
+func buildListInfo(lists map[string]docs.List) map[string][]listLevel {
+	m := map[string][]listLevel{}
 	for id, list := range lists {
 		if list.ListProperties == nil {
 			continue
 		}
-		listType := "ol"
-		if list.ListProperties.NestingLevels[0].GlyphType == "" ||
-			list.ListProperties.NestingLevels[0].GlyphType == "GLYPH_TYPE_UNSPECIFIED" {
-			listType = "ul"
+		levels := make([]listLevel, 0, len(list.ListProperties.NestingLevels))
+		for _, level := range list.ListProperties.NestingLevels {
+			listType := "ol"
+			if level.GlyphType == "" ||
+				level.GlyphType == "GLYPH_TYPE_UNSPECIFIED" {
+				listType = "ul"
+			}
+			levels = append(levels, listLevel{listType, level.StartNumber})
 		}
-		m[id] = listType
+		m[id] = levels
 	}
 	return m
 }
 
+// appendListItem adds an <li> to the list at depth,
+// continuing the last list at each level above it if possible.
+func appendListItem(n *html.Node, levels []listLevel, depth int) *html.Node {
+	for i := 0; ; i++ {
+		level := listLevel{tag: "ul"}
+		if i < len(levels) {
+			level = levels[i]
+		}
+		list := n.LastChild
+		if list == nil || list.Type != html.ElementNode || list.Data != level.tag {
+			list = xhtml.New(level.tag)
+			if level.tag == "ol" && level.start > 1 {
+				xhtml.SetAttr(list, "start", strconv.FormatInt(level.start, 10))
+			}
+			n.AppendChild(list)
+		}
+		if i >= depth {
+			li := xhtml.New("li")
+			list.AppendChild(li)
+			return li
+		}
+		n = xhtml.LastChildOrNew(list, "li")
+	}
+}
+
 func buildObjectInfo(objs map[string]docs.InlineObject) map[string]*html.Node {
 	m := make(map[string]*html.Node, len(objs))
 	for id, obj := range objs {
@@ -138,7 +222,7 @@ func buildObjectInfo(objs map[string]docs.InlineObject) map[string]*html.Node {
 	return m
 }
 
-func convertEl(n *html.Node, el *docs.StructuralElement, listInfo map[string]string, objInfo map[string]*html.Node) {
+func convertEl(n *html.Node, el *docs.StructuralElement, listInfo map[string][]listLevel, objInfo map[string]*html.Node, notes *footnoteInfo) {
 	if el.Table != nil && el.Table.TableRows != nil {
 		// Define empty cell for checking later
 		emptyCell := func() *html.Node {
@@ -160,7 +244,7 @@ func convertEl(n *html.Node, el *docs.StructuralElement, listInfo map[string]str
 						xhtml.SetAttr(cellEl, "colspan", strconv.Itoa(colspan))
 					}
 					for _, content := range cell.Content {
-						convertEl(cellEl, content, listInfo, objInfo)
+						convertEl(cellEl, content, listInfo, objInfo, notes)
 					}
 					if !xhtml.DeepEqual(cellEl, emptyCell) {
 						rowEl.AppendChild(cellEl)
@@ -172,12 +256,8 @@ func convertEl(n *html.Node, el *docs.StructuralElement, listInfo map[string]str
 	if el.Paragraph == nil {
 		return
 	}
-	if el.Paragraph.Bullet != nil {
-		listType := listInfo[el.Paragraph.Bullet.ListId]
-		ul := xhtml.LastChildOrNew(n, listType)
-		li := xhtml.New("li")
-		ul.AppendChild(li)
-		n = li
+	if bullet := el.Paragraph.Bullet; bullet != nil {
+		n = appendListItem(n, listInfo[bullet.ListId], int(bullet.NestingLevel))
 	}
 
 	blockType := tagForNamedStyle[el.Paragraph.ParagraphStyle.NamedStyleType]
@@ -203,6 +283,16 @@ func convertEl(n *html.Node, el *docs.StructuralElement, listInfo map[string]str
 			inner.AppendChild(link)
 		}
 
+		if ref := subel.FootnoteReference; ref != nil {
+			inner := xhtml.LastChildOrNew(n, blockType)
+			num := notes.add(ref.FootnoteId)
+			sup := xhtml.New("sup", "id", fmt.Sprintf("fnref-%d", num))
+			link := xhtml.New("a", "href", fmt.Sprintf("#fn-%d", num), "role", "doc-noteref")
+			xhtml.AppendText(link, strconv.Itoa(num))
+			sup.AppendChild(link)
+			inner.AppendChild(sup)
+		}
+
 		if subel.InlineObjectElement != nil {
 			inner := xhtml.LastChildOrNew(n, blockType)
 			el := objInfo[subel.InlineObjectElement.InlineObjectId]
@@ -0,0 +1,66 @@
+package gdocs
+
+import (
+	"strings"
+
+	"google.golang.org/api/docs/v1"
+)
+
+// Suggestion is a pending suggested edit in a document.
+type Suggestion struct {
+	ID   string
+	Kind string // "insertion" or "deletion"
+	Text string
+}
+
+// Suggestions lists the suggested insertions and deletions in doc
+// in the order they appear.
+// The document must have been requested with its suggestions inline.
+func Suggestions(doc *docs.Document) []Suggestion {
+	var (
+		suggestions []Suggestion
+		seen        = map[Suggestion]int{}
+	)
+	add := func(kind, id, text string) {
+		key := Suggestion{ID: id, Kind: kind}
+		if i, ok := seen[key]; ok {
+			suggestions[i].Text += text
+			return
+		}
+		seen[key] = len(suggestions)
+		suggestions = append(suggestions, Suggestion{id, kind, text})
+	}
+	if doc.Body != nil {
+		findSuggestions(doc.Body.Content, add)
+	}
+	for i := range suggestions {
+		suggestions[i].Text = strings.TrimSpace(suggestions[i].Text)
+	}
+	return suggestions
+}
+
+func findSuggestions(content []*docs.StructuralElement, add func(kind, id, text string)) {
+	for _, el := range content {
+		if el.Table != nil {
+			for _, row := range el.Table.TableRows {
+				for _, cell := range row.TableCells {
+					findSuggestions(cell.Content, add)
+				}
+			}
+		}
+		if el.Paragraph == nil {
+			continue
+		}
+		for _, subel := range el.Paragraph.Elements {
+			if subel.TextRun == nil {
+				continue
+			}
+			for _, id := range subel.TextRun.SuggestedInsertionIds {
+				add("insertion", id, subel.TextRun.Content)
+			}
+			for _, id := range subel.TextRun.SuggestedDeletionIds {
+				add("deletion", id, subel.TextRun.Content)
+			}
+		}
+	}
+}
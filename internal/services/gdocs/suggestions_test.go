@@ -0,0 +1,44 @@
+package gdocs
+
+import (
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"google.golang.org/api/docs/v1"
+)
+
+func TestSuggestions(t *testing.T) {
+	para := func(runs ...*docs.TextRun) *docs.StructuralElement {
+		p := &docs.Paragraph{}
+		for _, run := range runs {
+			p.Elements = append(p.Elements, &docs.ParagraphElement{TextRun: run})
+		}
+		return &docs.StructuralElement{Paragraph: p}
+	}
+	doc := &docs.Document{Body: &docs.Body{Content: []*docs.StructuralElement{
+		para(
+			&docs.TextRun{Content: "The governor "},
+			&docs.TextRun{Content: "signed", SuggestedDeletionIds: []string{"del1"}},
+			&docs.TextRun{Content: "vetoed", SuggestedInsertionIds: []string{"ins1"}},
+			&docs.TextRun{Content: " the bill.\n"},
+		),
+		{Table: &docs.Table{TableRows: []*docs.TableRow{{
+			TableCells: []*docs.TableCell{{
+				Content: []*docs.StructuralElement{
+					para(&docs.TextRun{Content: "Harris", SuggestedInsertionIds: []string{"ins2"}}),
+				},
+			}},
+		}}}},
+		para(
+			&docs.TextRun{Content: " burg \n", SuggestedInsertionIds: []string{"ins2"}},
+		),
+	}}}
+
+	be.AllEqual(t, []Suggestion{
+		{ID: "del1", Kind: "deletion", Text: "signed"},
+		{ID: "ins1", Kind: "insertion", Text: "vetoed"},
+		{ID: "ins2", Kind: "insertion", Text: "Harris burg"},
+	}, Suggestions(doc))
+
+	be.Zero(t, len(Suggestions(&docs.Document{})))
+}
@@ -6,7 +6,9 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"maps"
 	"net/http"
+	"slices"
 
 	"github.com/google/go-github/v53/github"
 	"github.com/spotlightpa/almanack/internal/almlog"
@@ -17,6 +19,9 @@ import (
 type ContentStore interface {
 	GetFile(ctx context.Context, path string) (content string, err error)
 	UpdateFile(ctx context.Context, msg, path string, content []byte) error
+	// UpdateFiles writes files, a map of paths to contents, in a single commit.
+	UpdateFiles(ctx context.Context, msg string, files map[string][]byte) error
+	DeleteFile(ctx context.Context, msg, path string) error
 }
 
 func AddFlags(fl *flag.FlagSet) func() ContentStore {
@@ -25,7 +30,11 @@ func AddFlags(fl *flag.FlagSet) func() ContentStore {
 	repo := fl.String("github-repo", "", "name of Github `repo`")
 	branch := fl.String("github-branch", "", "Github `branch` to use")
 	mock := fl.String("github-mock-path", "", "`path` for mock Github files")
+	gitPath := fl.String("github-git-path", "", "`path` to a local git repository to commit to instead of Github")
 	return func() ContentStore {
+		if *gitPath != "" {
+			return NewGitRepo(*gitPath)
+		}
 		if *token == "" || *owner == "" || *repo == "" || *branch == "" {
 			return NewMockClient(*mock)
 		}
@@ -144,6 +153,110 @@ func (cl *Client) UpdateFile(ctx context.Context, msg, path string, content []by
 	return err
 }
 
+// UpdateFiles uses the Git trees API to commit all files at once.
+// If the resulting tree is unchanged, no commit is made.
+// File contents must be text.
+func (cl *Client) UpdateFiles(ctx context.Context, msg string, files map[string][]byte) error {
+	l := almlog.FromContext(ctx)
+	paths := slices.Sorted(maps.Keys(files))
+	l.InfoContext(ctx, "github.UpdateFiles",
+		"org", cl.owner,
+		"repo", cl.repo,
+		"branch", cl.branch,
+		"paths", paths,
+	)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	ref, _, err := cl.client.Git.GetRef(ctx, cl.owner, cl.repo, "heads/"+cl.branch)
+	if err != nil {
+		return err
+	}
+	parent, _, err := cl.client.Git.GetCommit(ctx, cl.owner, cl.repo, ref.GetObject().GetSHA())
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		entries = append(entries, &github.TreeEntry{
+			Path:    new(path),
+			Mode:    new("100644"),
+			Type:    new("blob"),
+			Content: new(string(files[path])),
+		})
+	}
+	tree, _, err := cl.client.Git.CreateTree(ctx, cl.owner, cl.repo, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return err
+	}
+	if tree.GetSHA() == parent.GetTree().GetSHA() {
+		l.InfoContext(ctx, "github.UpdateFiles skipping; already updated",
+			"org", cl.owner,
+			"repo", cl.repo,
+			"branch", cl.branch,
+			"paths", paths,
+		)
+		return nil
+	}
+
+	commit, _, err := cl.client.Git.CreateCommit(ctx, cl.owner, cl.repo, &github.Commit{
+		Message: new(msg),
+		Tree:    tree,
+		Parents: []*github.Commit{{SHA: parent.SHA}},
+		Author:  makeAuthor(ctx),
+	})
+	if err != nil {
+		return err
+	}
+
+	ref.Object.SHA = commit.SHA
+	_, _, err = cl.client.Git.UpdateRef(ctx, cl.owner, cl.repo, ref, false)
+	return err
+}
+
+func (cl *Client) DeleteFile(ctx context.Context, msg, path string) error {
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "github.DeleteFile",
+		"org", cl.owner,
+		"repo", cl.repo,
+		"branch", cl.branch,
+		"path", path,
+	)
+
+	fileInfo, _, _, err := cl.client.Repositories.GetContents(
+		ctx,
+		cl.owner,
+		cl.repo,
+		path,
+		&github.RepositoryContentGetOptions{Ref: cl.branch})
+	if err != nil {
+		resp := new(github.ErrorResponse)
+		if errors.As(err, &resp) && resp.Response.StatusCode == http.StatusNotFound {
+			l.InfoContext(ctx, "github.DeleteFile skipping; already deleted",
+				"org", cl.owner,
+				"repo", cl.repo,
+				"branch", cl.branch,
+				"path", path,
+			)
+			return nil
+		}
+		return err
+	}
+
+	opts := &github.RepositoryContentFileOptions{
+		Message: new(msg),
+		Branch:  new(cl.branch),
+		SHA:     fileInfo.SHA,
+		Author:  makeAuthor(ctx),
+	}
+
+	_, _, err = cl.client.Repositories.DeleteFile(ctx, cl.owner, cl.repo, path, opts)
+
+	return err
+}
+
 func (cl *Client) Ping(ctx context.Context) error {
 	l := almlog.FromContext(ctx)
 	l.InfoContext(ctx, "github.Ping",
@@ -0,0 +1,252 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spotlightpa/almanack/internal/almlog"
+)
+
+// GitRepo is a ContentStore backed by a git repository on disk.
+// Unlike MockClient, it makes real commits, so it can be used
+// to check commit messages and authors without network access.
+// It requires the git command to be installed.
+type GitRepo struct {
+	dir string
+
+	mu       sync.Mutex
+	initOnce sync.Once
+	initErr  error
+}
+
+func NewGitRepo(dirs ...string) *GitRepo {
+	dir := filepath.Join(dirs...)
+	almlog.Logger.Warn("using local git repo for Github", "dir", dir)
+	return &GitRepo{dir: dir}
+}
+
+// GitCommit is a commit returned by GitRepo.Log.
+type GitCommit struct {
+	SHA         string
+	AuthorName  string
+	AuthorEmail string
+	Date        time.Time
+	Subject     string
+	Paths       []string
+}
+
+func (gr *GitRepo) abspath(path string) string {
+	return filepath.Join(gr.dir, path)
+}
+
+func (gr *GitRepo) git(ctx context.Context, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{
+		"-c", "commit.gpgsign=false",
+		"-c", "core.quotePath=false",
+	}, args...)...)
+	cmd.Dir = gr.dir
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("git %s: %w: %s",
+			args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+func (gr *GitRepo) ensureRepo(ctx context.Context) error {
+	gr.initOnce.Do(func() {
+		if gr.initErr = os.MkdirAll(gr.dir, os.ModePerm); gr.initErr != nil {
+			return
+		}
+		if _, err := os.Stat(filepath.Join(gr.dir, ".git")); err == nil {
+			return
+		}
+		_, gr.initErr = gr.git(ctx, nil, "init", "--quiet")
+	})
+	return gr.initErr
+}
+
+// hasChanges reports whether the files at paths differ from HEAD.
+func (gr *GitRepo) hasChanges(ctx context.Context, paths []string) (bool, error) {
+	args := append([]string{"status", "--porcelain", "--"}, paths...)
+	out, err := gr.git(ctx, nil, args...)
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
+
+func (gr *GitRepo) commit(ctx context.Context, msg string, paths []string) error {
+	author := makeAuthor(ctx)
+	env := []string{
+		"GIT_AUTHOR_NAME=" + author.GetName(),
+		"GIT_AUTHOR_EMAIL=" + author.GetEmail(),
+		"GIT_COMMITTER_NAME=" + author.GetName(),
+		"GIT_COMMITTER_EMAIL=" + author.GetEmail(),
+	}
+	args := append([]string{
+		"commit", "--quiet", "--no-verify", "--message", msg, "--",
+	}, paths...)
+	_, err := gr.git(ctx, env, args...)
+	return err
+}
+
+func (gr *GitRepo) GetFile(ctx context.Context, path string) (contents string, err error) {
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "github.git.GetFile",
+		"dir", gr.dir,
+		"path", path,
+	)
+	if err = gr.ensureRepo(ctx); err != nil {
+		return "", err
+	}
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	b, err := gr.git(ctx, nil, "show", "HEAD:"+filepath.ToSlash(path))
+	return string(b), err
+}
+
+func (gr *GitRepo) UpdateFile(ctx context.Context, msg, path string, content []byte) error {
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "github.git.UpdateFile",
+		"dir", gr.dir,
+		"path", path,
+	)
+	return gr.updateFiles(ctx, msg, map[string][]byte{path: content})
+}
+
+func (gr *GitRepo) UpdateFiles(ctx context.Context, msg string, files map[string][]byte) error {
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "github.git.UpdateFiles",
+		"dir", gr.dir,
+		"paths", slices.Sorted(maps.Keys(files)),
+	)
+	return gr.updateFiles(ctx, msg, files)
+}
+
+func (gr *GitRepo) updateFiles(ctx context.Context, msg string, files map[string][]byte) error {
+	if len(files) == 0 {
+		return nil
+	}
+	if err := gr.ensureRepo(ctx); err != nil {
+		return err
+	}
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	paths := slices.Sorted(maps.Keys(files))
+	for _, path := range paths {
+		fn := gr.abspath(path)
+		if err := os.MkdirAll(filepath.Dir(fn), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.WriteFile(fn, files[path], 0644); err != nil {
+			return err
+		}
+	}
+	if changed, err := gr.hasChanges(ctx, paths); err != nil || !changed {
+		if err == nil {
+			almlog.FromContext(ctx).InfoContext(ctx, "github.git.UpdateFiles skipping; already updated",
+				"dir", gr.dir,
+				"paths", paths,
+			)
+		}
+		return err
+	}
+	args := append([]string{"add", "--"}, paths...)
+	if _, err := gr.git(ctx, nil, args...); err != nil {
+		return err
+	}
+	return gr.commit(ctx, msg, paths)
+}
+
+func (gr *GitRepo) DeleteFile(ctx context.Context, msg, path string) error {
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "github.git.DeleteFile",
+		"dir", gr.dir,
+		"path", path,
+	)
+	if err := gr.ensureRepo(ctx); err != nil {
+		return err
+	}
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	out, err := gr.git(ctx, nil, "ls-files", "--", path)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(out)) == 0 {
+		l.InfoContext(ctx, "github.git.DeleteFile skipping; already deleted",
+			"dir", gr.dir,
+			"path", path,
+		)
+		return nil
+	}
+	if _, err = gr.git(ctx, nil, "rm", "--quiet", "--", path); err != nil {
+		return err
+	}
+	return gr.commit(ctx, msg, []string{path})
+}
+
+// Log returns the commits touching path, newest first.
+// If path is blank, it returns all commits.
+func (gr *GitRepo) Log(ctx context.Context, path string) ([]GitCommit, error) {
+	if err := gr.ensureRepo(ctx); err != nil {
+		return nil, err
+	}
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	// A repo without any commits has no HEAD
+	if _, err := gr.git(ctx, nil, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return nil, nil
+	}
+	args := []string{"log", "--name-only", "--format=%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%s"}
+	if path != "" {
+		args = append(args, "--", path)
+	}
+	out, err := gr.git(ctx, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []GitCommit
+	for record := range strings.SplitSeq(string(out), "\x1e") {
+		header, names, _ := strings.Cut(record, "\n")
+		fields := strings.Split(header, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			return nil, err
+		}
+		c := GitCommit{
+			SHA:         fields[0],
+			AuthorName:  fields[1],
+			AuthorEmail: fields[2],
+			Date:        date,
+			Subject:     fields[4],
+		}
+		for name := range strings.Lines(names) {
+			if name = strings.TrimSpace(name); name != "" {
+				c.Paths = append(c.Paths, name)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
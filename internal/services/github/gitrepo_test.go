@@ -0,0 +1,65 @@
+package github_test
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/almlog"
+	"github.com/spotlightpa/almanack/internal/services/github"
+)
+
+func TestGitRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	almlog.UseTestLogger(t)
+	ctx := t.Context()
+	repo := github.NewGitRepo(t.TempDir())
+
+	// empty repo
+	commits, err := repo.Log(ctx, "")
+	be.NilErr(t, err)
+	be.Zero(t, commits)
+	_, err = repo.GetFile(ctx, "a.txt")
+	be.Nonzero(t, err)
+
+	// create
+	be.NilErr(t, repo.UpdateFile(ctx, "create a", "a.txt", []byte("a1")))
+	content, err := repo.GetFile(ctx, "a.txt")
+	be.NilErr(t, err)
+	be.Equal(t, "a1", content)
+
+	// no-op update
+	be.NilErr(t, repo.UpdateFile(ctx, "update a again", "a.txt", []byte("a1")))
+	commits, err = repo.Log(ctx, "")
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(commits))
+	be.Equal(t, "create a", commits[0].Subject)
+	be.Equal(t, "Almanack", commits[0].AuthorName)
+	be.Equal(t, "webmaster@spotlightpa.org", commits[0].AuthorEmail)
+
+	// multiple files
+	be.NilErr(t, repo.UpdateFiles(ctx, "update several", map[string][]byte{
+		"a.txt":     []byte("a2"),
+		"dir/b.txt": []byte("b1"),
+	}))
+	commits, err = repo.Log(ctx, "")
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(commits))
+	be.Equal(t, "update several", commits[0].Subject)
+	be.AllEqual(t, []string{"a.txt", "dir/b.txt"}, commits[0].Paths)
+	content, err = repo.GetFile(ctx, "dir/b.txt")
+	be.NilErr(t, err)
+	be.Equal(t, "b1", content)
+
+	// delete
+	be.NilErr(t, repo.DeleteFile(ctx, "delete b", "dir/b.txt"))
+	be.NilErr(t, repo.DeleteFile(ctx, "delete b again", "dir/b.txt"))
+	commits, err = repo.Log(ctx, "dir/b.txt")
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(commits))
+	be.Equal(t, "delete b", commits[0].Subject)
+	_, err = repo.GetFile(ctx, "dir/b.txt")
+	be.Nonzero(t, err)
+}
@@ -2,6 +2,7 @@ package github
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -57,6 +58,28 @@ func (mc *MockClient) UpdateFile(ctx context.Context, msg, path string, content
 	return os.WriteFile(tmpfn, content, 0644)
 }
 
+func (mc *MockClient) UpdateFiles(ctx context.Context, msg string, files map[string][]byte) error {
+	for path, content := range files {
+		if err := mc.UpdateFile(ctx, msg, path, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mc *MockClient) DeleteFile(ctx context.Context, msg, path string) error {
+	tmpfn := mc.abspath(path)
+	l := almlog.FromContext(ctx)
+	l.InfoContext(ctx, "github.mock.DeleteFile",
+		"path", tmpfn,
+	)
+	err := os.Remove(tmpfn)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
 // ErrorClient is a test client that just always returns an error.
 type ErrorClient struct {
 	Error error
@@ -73,3 +96,11 @@ func (ec ErrorClient) GetFile(ctx context.Context, path string) (contents string
 func (ec ErrorClient) UpdateFile(ctx context.Context, msg, path string, content []byte) error {
 	return ec.Error
 }
+
+func (ec ErrorClient) UpdateFiles(ctx context.Context, msg string, files map[string][]byte) error {
+	return ec.Error
+}
+
+func (ec ErrorClient) DeleteFile(ctx context.Context, msg, path string) error {
+	return ec.Error
+}
@@ -0,0 +1,57 @@
+package google
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/carlmjohnson/requests"
+)
+
+// Comments lists the comment threads on a Drive file that haven't been resolved.
+func (gsvc *Service) Comments(ctx context.Context, cl *http.Client, fileID string) (comments []Comment, err error) {
+	pageToken := ""
+	for {
+		var list CommentList
+		if err = requests.
+			URL("https://www.googleapis.com").
+			Pathf("/drive/v3/files/%s/comments", fileID).
+			Param("fields", "nextPageToken,comments(id,content,quotedFileContent/value,resolved,author/displayName)").
+			Param("pageSize", "100").
+			ParamOptional("pageToken", pageToken).
+			Client(cl).
+			ToJSON(&list).
+			Fetch(ctx); err != nil {
+			return nil, err
+		}
+		for _, c := range list.Comments {
+			if !c.Resolved {
+				comments = append(comments, c)
+			}
+		}
+		if list.NextPageToken == "" {
+			return comments, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+type CommentList struct {
+	Comments      []Comment `json:"comments"`
+	NextPageToken string    `json:"nextPageToken"`
+}
+
+type Comment struct {
+	ID                string            `json:"id"`
+	Content           string            `json:"content"`
+	Resolved          bool              `json:"resolved"`
+	Author            CommentAuthor     `json:"author"`
+	QuotedFileContent QuotedFileContent `json:"quotedFileContent"`
+}
+
+type CommentAuthor struct {
+	DisplayName string `json:"displayName"`
+}
+
+type QuotedFileContent struct {
+	Value string `json:"value"`
+}
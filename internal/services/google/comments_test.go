@@ -0,0 +1,25 @@
+package google
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/carlmjohnson/requests/reqtest"
+)
+
+func TestComments(t *testing.T) {
+	var gsvc Service
+	cl := *http.DefaultClient
+	cl.Transport = reqtest.ReplayJSON(http.StatusOK, CommentList{
+		Comments: []Comment{
+			{ID: "a", Content: "Source?", QuotedFileContent: QuotedFileContent{"12 percent"}},
+			{ID: "b", Content: "Fixed", Resolved: true},
+		},
+	})
+	comments, err := gsvc.Comments(t.Context(), &cl, "doc")
+	be.NilErr(t, err)
+	be.Equal(t, 1, len(comments))
+	be.Equal(t, "a", comments[0].ID)
+	be.Equal(t, "12 percent", comments[0].QuotedFileContent.Value)
+}
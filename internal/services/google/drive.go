@@ -3,8 +3,10 @@ package google
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/carlmjohnson/requests"
 	"github.com/earthboundkid/bytemap/v2"
@@ -45,6 +47,48 @@ func (gsvc *Service) Files(ctx context.Context, cl *http.Client) (files []*Files
 	return
 }
 
+// ModifiedDocs returns the IDs of the Google Docs visible to the client
+// that were changed by anyone after since, with when each was last changed.
+func (gsvc *Service) ModifiedDocs(ctx context.Context, cl *http.Client, since time.Time) (map[string]time.Time, error) {
+	modified := make(map[string]time.Time)
+	q := fmt.Sprintf(
+		"mimeType='application/vnd.google-apps.document' and modifiedTime > '%s'",
+		since.UTC().Format(time.RFC3339))
+	pageToken := ""
+	for {
+		var list struct {
+			NextPageToken string `json:"nextPageToken"`
+			Files         []struct {
+				ID           string    `json:"id"`
+				ModifiedTime time.Time `json:"modifiedTime"`
+			} `json:"files"`
+		}
+		rb := requests.
+			URL("https://www.googleapis.com/drive/v3/files").
+			Param("corpora", "allDrives").
+			Param("includeItemsFromAllDrives", "true").
+			Param("supportsAllDrives", "true").
+			Param("q", q).
+			Param("fields", "nextPageToken,files(id,modifiedTime)").
+			Param("pageSize", "1000").
+			Client(cl).
+			ToJSON(&list)
+		if pageToken != "" {
+			rb.Param("pageToken", pageToken)
+		}
+		if err := rb.Fetch(ctx); err != nil {
+			return nil, err
+		}
+		for _, f := range list.Files {
+			modified[f.ID] = f.ModifiedTime
+		}
+		if list.NextPageToken == "" {
+			return modified, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
 type FileList struct {
 	Files []*Files `json:"files"`
 }
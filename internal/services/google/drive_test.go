@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/carlmjohnson/be"
 	"github.com/carlmjohnson/requests/reqtest"
@@ -45,3 +46,19 @@ func TestDownloadFile(t *testing.T) {
 	be.Nonzero(t, err)
 	be.Zero(t, b)
 }
+
+func TestModifiedDocs(t *testing.T) {
+	var gsvc Service
+	cl := *http.DefaultClient
+	cl.Transport = reqtest.ReplayJSON(http.StatusOK, map[string]any{
+		"files": []map[string]string{
+			{"id": "doc1", "modifiedTime": "2026-03-04T15:04:05.123Z"},
+			{"id": "doc2", "modifiedTime": "2026-03-04T16:00:00Z"},
+		},
+	})
+	modified, err := gsvc.ModifiedDocs(t.Context(), &cl, time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC))
+	be.NilErr(t, err)
+	be.Equal(t, 2, len(modified))
+	be.Equal(t, time.Date(2026, 3, 4, 15, 4, 5, 123e6, time.UTC), modified["doc1"])
+	be.Equal(t, time.Date(2026, 3, 4, 16, 0, 0, 0, time.UTC), modified["doc2"])
+}
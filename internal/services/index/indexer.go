@@ -26,6 +26,7 @@ func AddFlags(fl *flag.FlagSet) func() Indexer {
 
 type Indexer interface {
 	SaveObject(object any, opts ...any) (res search.SaveObjectRes, err error)
+	DeleteObject(objectID string, opts ...any) (res search.DeleteTaskRes, err error)
 }
 
 type MockIndexer struct {
@@ -43,3 +44,16 @@ func (mi MockIndexer) SaveObject(object any, opts ...any) (res search.SaveObject
 	l.InfoContext(ctx, "index.Mock.SaveObject")
 	return
 }
+
+func (mi MockIndexer) DeleteObject(objectID string, opts ...any) (res search.DeleteTaskRes, err error) {
+	l := almlog.Logger
+	var ctx context.Context
+	var ok bool
+	for _, opt := range opts {
+		if ctx, ok = opt.(context.Context); ok {
+			l = almlog.FromContext(ctx)
+		}
+	}
+	l.InfoContext(ctx, "index.Mock.DeleteObject", "objectID", objectID)
+	return
+}
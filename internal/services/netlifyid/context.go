@@ -12,11 +12,15 @@ type netlifyidContextType int
 const netlifyidContextKey netlifyidContextType = iota
 
 func addJWTToRequest(id *JWT, r *http.Request) *http.Request {
-	ctx := context.WithValue(r.Context(), netlifyidContextKey, id)
+	return r.WithContext(NewContext(r.Context(), id))
+}
+
+// NewContext returns a context carrying id and a logger tagged with its email.
+func NewContext(ctx context.Context, id *JWT) context.Context {
+	ctx = context.WithValue(ctx, netlifyidContextKey, id)
 	l := almlog.FromContext(ctx).
 		With("user.email", id.User.Email)
-
-	return r.WithContext(almlog.NewContext(ctx, l))
+	return almlog.NewContext(ctx, l)
 }
 
 func FromContext(ctx context.Context) *JWT {
@@ -0,0 +1,110 @@
+package stringx
+
+import (
+	"slices"
+	"strings"
+)
+
+// DiffOp is the kind of change a LineDiff represents.
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "="
+	DiffInsert DiffOp = "+"
+	DiffDelete DiffOp = "-"
+)
+
+// LineDiff is a single line in the output of DiffLines.
+type LineDiff struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxDiffCells bounds the size of the LCS table used by DiffLines.
+const maxDiffCells = 4_000_000
+
+// DiffLines returns a line by line edit script that transforms a into b.
+// Very large inputs fall back to replacing every differing line.
+func DiffLines(a, b string) []LineDiff {
+	as, bs := splitLines(a), splitLines(b)
+
+	// Trim common prefix and suffix to keep the LCS table small
+	prefix := 0
+	for prefix < len(as) && prefix < len(bs) && as[prefix] == bs[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(as)-prefix && suffix < len(bs)-prefix &&
+		as[len(as)-1-suffix] == bs[len(bs)-1-suffix] {
+		suffix++
+	}
+
+	diffs := make([]LineDiff, 0, len(as)+len(bs))
+	for _, line := range as[:prefix] {
+		diffs = append(diffs, LineDiff{DiffEqual, line})
+	}
+	diffs = append(diffs, diffMiddle(
+		as[prefix:len(as)-suffix],
+		bs[prefix:len(bs)-suffix],
+	)...)
+	for _, line := range as[len(as)-suffix:] {
+		diffs = append(diffs, LineDiff{DiffEqual, line})
+	}
+	return diffs
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+func diffMiddle(as, bs []string) []LineDiff {
+	var diffs []LineDiff
+	if len(as)*len(bs) > maxDiffCells {
+		for _, line := range as {
+			diffs = append(diffs, LineDiff{DiffDelete, line})
+		}
+		for _, line := range bs {
+			diffs = append(diffs, LineDiff{DiffInsert, line})
+		}
+		return diffs
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of as[i:] and bs[j:]
+	width := len(bs) + 1
+	lcs := make([]int, (len(as)+1)*width)
+	for i := len(as) - 1; i >= 0; i-- {
+		for j := len(bs) - 1; j >= 0; j-- {
+			if as[i] == bs[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(as) && j < len(bs) {
+		switch {
+		case as[i] == bs[j]:
+			diffs = append(diffs, LineDiff{DiffEqual, as[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			diffs = append(diffs, LineDiff{DiffDelete, as[i]})
+			i++
+		default:
+			diffs = append(diffs, LineDiff{DiffInsert, bs[j]})
+			j++
+		}
+	}
+	for _, line := range as[i:] {
+		diffs = append(diffs, LineDiff{DiffDelete, line})
+	}
+	for _, line := range bs[j:] {
+		diffs = append(diffs, LineDiff{DiffInsert, line})
+	}
+	return slices.Clip(diffs)
+}
@@ -0,0 +1,34 @@
+package stringx_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmjohnson/be"
+	"github.com/spotlightpa/almanack/internal/utils/stringx"
+)
+
+func TestDiffLines(t *testing.T) {
+	cases := map[string]struct {
+		a, b string
+		want string
+	}{
+		"both empty": {"", "", ""},
+		"same":       {"a\nb", "a\nb", "=a =b"},
+		"insert":     {"", "a\nb", "+a +b"},
+		"delete":     {"a\nb", "", "-a -b"},
+		"middle":     {"a\nb\nc", "a\nx\nc", "=a -b +x =c"},
+		"append":     {"a\nb", "a\nb\nc", "=a =b +c"},
+		"reorder":    {"a\nb\nc\nd", "a\nc\nb\nd", "=a -b =c +b =d"},
+		"blank line": {"a\n\nb", "a\nb", "=a - =b"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, d := range stringx.DiffLines(tc.a, tc.b) {
+				got = append(got, string(d.Op)+d.Text)
+			}
+			be.Equal(t, tc.want, strings.Join(got, " "))
+		})
+	}
+}
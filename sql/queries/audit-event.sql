@@ -0,0 +1,24 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_event ("email_address", "route", "target", "status_code", "before", "after")
+  VALUES (@email_address, @route, @target, @status_code, @before, @after);
+
+-- ListAuditEvents lists the events matching every filter that is set,
+-- newest first, starting before the cursor.
+-- name: ListAuditEvents :many
+SELECT
+  *
+FROM
+  audit_event
+WHERE (@email_address::text = ''
+  OR email_address ILIKE @email_address::text)
+AND (@target::text = ''
+  OR target = @target::text)
+AND (sqlc.narg(created_after)::timestamptz IS NULL
+  OR created_at >= sqlc.narg(created_after)::timestamptz)
+AND (sqlc.narg(created_before)::timestamptz IS NULL
+  OR created_at < sqlc.narg(created_before)::timestamptz)
+AND (@cursor_id::bigint = 0
+  OR id < @cursor_id::bigint)
+ORDER BY
+  id DESC
+LIMIT @limit;
@@ -0,0 +1,61 @@
+-- name: ListAuthors :many
+SELECT
+  author.*,
+  coalesce(image.path, '')::text AS "photo_path"
+FROM
+  author
+  LEFT JOIN image ON image.id = author.photo_id
+ORDER BY
+  author.name ASC,
+  author.id ASC;
+
+-- ListAuthorsByNames finds the authors whose slug or lowercased name
+-- is in the list.
+-- name: ListAuthorsByNames :many
+SELECT
+  author.*,
+  coalesce(image.path, '')::text AS "photo_path"
+FROM
+  author
+  LEFT JOIN image ON image.id = author.photo_id
+WHERE
+  author.slug = ANY (@slugs::text[])
+  OR lower(author.name) = ANY (@names::text[]);
+
+-- name: GetAuthorByID :one
+SELECT
+  *
+FROM
+  author
+WHERE
+  id = @id;
+
+-- name: CreateAuthor :one
+INSERT INTO author ("name", "slug", "title", "bio", "photo_id", "email",
+  "social_links", "active")
+  VALUES (@name, @slug, @title, @bio, @photo_id, @email, @social_links, @active)
+RETURNING
+  *;
+
+-- name: UpdateAuthor :one
+UPDATE
+  author
+SET
+  "name" = @name,
+  "slug" = @slug,
+  "title" = @title,
+  "bio" = @bio,
+  "photo_id" = @photo_id,
+  "email" = @email,
+  "social_links" = @social_links,
+  "active" = @active
+WHERE
+  id = @id
+RETURNING
+  *;
+
+-- name: DeleteAuthor :one
+DELETE FROM author
+WHERE id = @id
+RETURNING
+  *;
@@ -0,0 +1,60 @@
+-- ListCalendarEvents returns pages scheduled but not yet published,
+-- published pages, scheduled site data changes, and shared article embargoes
+-- between starts_at and ends_at, in order.
+-- name: ListCalendarEvents :many
+SELECT
+  *
+FROM (
+  SELECT
+    'scheduled-page'::text AS "kind",
+    id,
+    coalesce(nullif(frontmatter ->> 'internal-id', ''), frontmatter ->> 'title', file_path)::text AS "title",
+    file_path::text AS "key",
+    schedule_for::timestamptz AS "at"
+  FROM
+    page
+  WHERE
+    schedule_for >= @starts_at::timestamptz
+    AND schedule_for < @ends_at::timestamptz
+    AND last_published IS NULL
+  UNION ALL
+  SELECT
+    'published-page'::text,
+    id,
+    coalesce(nullif(frontmatter ->> 'internal-id', ''), frontmatter ->> 'title', file_path)::text,
+    file_path::text,
+    publication_date::timestamptz
+  FROM
+    page
+  WHERE
+    last_published IS NOT NULL
+    AND publication_date >= @starts_at::timestamptz
+    AND publication_date < @ends_at::timestamptz
+  UNION ALL
+  SELECT
+    'site-data'::text,
+    id,
+    "key"::text,
+    "key"::text,
+    schedule_for::timestamptz
+  FROM
+    site_data
+  WHERE
+    schedule_for >= @starts_at::timestamptz
+    AND schedule_for < @ends_at::timestamptz
+  UNION ALL
+  SELECT
+    'embargo'::text,
+    id,
+    coalesce(nullif(internal_id, ''), hed)::text,
+    source_id::text,
+    embargo_until::timestamptz
+  FROM
+    shared_article
+  WHERE
+    embargo_until >= @starts_at::timestamptz
+    AND embargo_until < @ends_at::timestamptz) AS events
+ORDER BY
+  "at" ASC,
+  "kind" ASC,
+  id ASC;
@@ -1,6 +1,6 @@
 -- name: CreateGDocsDoc :one
-INSERT INTO g_docs_doc ("external_id", "document")
-  VALUES (@external_id, @document)
+INSERT INTO g_docs_doc ("external_id", "document", "unresolved")
+  VALUES (@external_id, @document, coalesce(@unresolved::text[], '{}'))
 RETURNING
   *;
 
@@ -66,6 +66,8 @@ FROM
 WHERE
   external_id = $1;
 
+-- DeleteGDocsDocWhereUnunused keeps the newest import of each document
+-- that ListGDocsWatched watches so WatchGDocs can tell when it was last imported.
 -- name: DeleteGDocsDocWhereUnunused :exec
 DELETE FROM g_docs_doc
 WHERE id NOT IN (
@@ -75,4 +77,62 @@ WHERE id NOT IN (
       shared_article
     WHERE
       source_type = 'gdocs')
+  AND id NOT IN (
+    SELECT DISTINCT ON (external_id)
+      id
+    FROM
+      g_docs_doc
+    WHERE
+      external_id IN (
+        SELECT
+          source_id
+        FROM
+          shared_article
+        WHERE
+          source_type = 'gdocs'
+          AND status IN ('P', 'S')
+        UNION
+        SELECT
+          source_id
+        FROM
+          page
+        WHERE
+          source_type = 'gdocs'
+          AND (expire_at IS NULL
+            OR expire_at > CURRENT_TIMESTAMP))
+    ORDER BY
+      external_id,
+      created_at DESC)
   AND processed_at < CURRENT_TIMESTAMP - interval '1 hour';
+
+-- ListGDocsWatched returns the documents behind shared articles that are
+-- still shared or in preview and pages that haven't expired
+-- and when each was last imported.
+-- name: ListGDocsWatched :many
+SELECT
+  external_id,
+  max(created_at)::timestamptz AS "last_imported_at"
+FROM
+  g_docs_doc
+WHERE
+  external_id IN (
+    SELECT
+      source_id
+    FROM
+      shared_article
+    WHERE
+      source_type = 'gdocs'
+      AND status IN ('P', 'S')
+    UNION
+    SELECT
+      source_id
+    FROM
+      page
+    WHERE
+      source_type = 'gdocs'
+      AND (expire_at IS NULL
+        OR expire_at > CURRENT_TIMESTAMP))
+GROUP BY
+  external_id
+ORDER BY
+  external_id;
@@ -0,0 +1,75 @@
+-- name: CreateJob :one
+INSERT INTO job ("kind", "params", "created_by")
+  VALUES (@kind, @params, @created_by)
+RETURNING
+  *;
+
+-- CreateJobUnlessRecent queues a job without params
+-- unless a job of the same kind was created since created_since.
+-- name: CreateJobUnlessRecent :one
+INSERT INTO job ("kind")
+SELECT
+  @kind::text
+WHERE
+  NOT EXISTS (
+    SELECT
+      1
+    FROM
+      job
+    WHERE
+      kind = @kind::text
+      AND created_at > @created_since)
+RETURNING
+  *;
+
+-- name: GetJobByID :one
+SELECT
+  *
+FROM
+  job
+WHERE
+  id = $1;
+
+-- PopPendingJob marks the oldest unstarted job as started and returns it.
+-- name: PopPendingJob :one
+UPDATE
+  job
+SET
+  started_at = CURRENT_TIMESTAMP
+WHERE
+  id = (
+    SELECT
+      id
+    FROM
+      job
+    WHERE
+      started_at IS NULL
+    ORDER BY
+      id ASC
+    LIMIT 1
+    FOR UPDATE
+      SKIP LOCKED)
+RETURNING
+  *;
+
+-- name: UpdateJobProgress :exec
+UPDATE
+  job
+SET
+  total = @total,
+  processed = @processed,
+  warnings = @warnings
+WHERE
+  id = @id;
+
+-- name: FinishJob :one
+UPDATE
+  job
+SET
+  result = @result,
+  error = @error,
+  finished_at = CURRENT_TIMESTAMP
+WHERE
+  id = @id
+RETURNING
+  *;
@@ -0,0 +1,80 @@
+-- name: UpsertPageLinks :exec
+INSERT INTO link_check ("page_id", "url", "source", "internal")
+SELECT
+  @page_id::bigint,
+  unnest(@urls::text[]),
+  unnest(@sources::text[]),
+  unnest(@internal::boolean[])
+ON CONFLICT ("page_id", "url")
+  DO UPDATE SET
+    "source" = EXCLUDED."source",
+    "internal" = EXCLUDED."internal";
+
+-- name: DeletePageLinksExcept :exec
+DELETE FROM link_check
+WHERE "page_id" = @page_id
+  AND NOT ("url" = ANY (@urls::text[]));
+
+-- ListLinksToCheck returns URLs on published pages that have not been checked
+-- since checked_before, starting with the ones that have never been checked.
+-- name: ListLinksToCheck :many
+SELECT
+  link_check.url
+FROM
+  link_check
+  JOIN page ON page.id = link_check.page_id
+WHERE
+  page.last_published IS NOT NULL
+  AND (link_check.checked_at IS NULL
+    OR link_check.checked_at < @checked_before)
+GROUP BY
+  link_check.url
+ORDER BY
+  bool_or(link_check.checked_at IS NULL) DESC,
+  min(link_check.checked_at) ASC
+LIMIT @limit;
+
+-- name: UpdateLinkCheck :exec
+UPDATE
+  link_check
+SET
+  "status" = @status,
+  "error" = @error,
+  "broken" = @broken,
+  "checked_at" = CURRENT_TIMESTAMP
+WHERE
+  "url" = @url;
+
+-- name: ListBrokenLinks :many
+SELECT
+  link_check.page_id,
+  page.file_path,
+  page.url_path,
+  link_check.url,
+  link_check.source,
+  link_check.internal,
+  link_check.status,
+  link_check.error,
+  link_check.checked_at
+FROM
+  link_check
+  JOIN page ON page.id = link_check.page_id
+WHERE
+  link_check.broken
+  AND page.last_published IS NOT NULL
+ORDER BY
+  page.file_path ASC,
+  link_check.url ASC;
+
+-- HasPublishedPagesInSection reports whether any published page
+-- is in the top level content directory named section.
+-- name: HasPublishedPagesInSection :one
+SELECT
+  EXISTS (
+    SELECT
+      1
+    FROM
+      page
+    WHERE
+      file_path LIKE 'content/' || @section::text || '/%'
+      AND last_published IS NOT NULL);
@@ -0,0 +1,54 @@
+-- name: ListPageCorrections :many
+SELECT
+  *
+FROM
+  page_correction
+WHERE
+  page_id = @page_id
+ORDER BY
+  corrected_at ASC,
+  id ASC;
+
+-- name: CreatePageCorrection :one
+INSERT INTO page_correction ("page_id", "kind", "note", "corrected_at", "created_by")
+  VALUES (@page_id, @kind, @note, @corrected_at, @created_by)
+RETURNING
+  *;
+
+-- name: UpdatePageCorrection :one
+UPDATE
+  page_correction
+SET
+  "kind" = @kind,
+  "note" = @note,
+  "corrected_at" = @corrected_at
+WHERE
+  id = @id
+RETURNING
+  *;
+
+-- name: DeletePageCorrection :one
+DELETE FROM page_correction
+WHERE id = @id
+RETURNING
+  *;
+
+-- ListPublishedCorrections returns the newest corrections
+-- that have been published along with their pages.
+-- name: ListPublishedCorrections :many
+SELECT
+  page_correction.kind,
+  page_correction.note,
+  page_correction.corrected_at,
+  coalesce(page.url_path, '')::text AS "url_path",
+  coalesce(page.frontmatter ->> 'title', '')::text AS "title"
+FROM
+  page_correction
+  JOIN page ON page.id = page_correction.page_id
+WHERE
+  page.last_published IS NOT NULL
+  AND page_correction.updated_at <= page.last_published
+ORDER BY
+  page_correction.corrected_at DESC,
+  page_correction.id DESC
+LIMIT @limit;
@@ -0,0 +1,30 @@
+-- name: UpsertPageLock :one
+INSERT INTO page_lock ("page_id", "email", "name")
+  VALUES (@page_id, @email, @name)
+ON CONFLICT ("page_id", "email")
+  DO UPDATE SET
+    "name" = EXCLUDED.name,
+    "heartbeat_at" = CURRENT_TIMESTAMP
+  RETURNING
+    *;
+
+-- name: ListPageLocks :many
+SELECT
+  *
+FROM
+  page_lock
+WHERE
+  page_id = @page_id
+  AND email <> @except_email::text
+  AND heartbeat_at > @since::timestamptz
+ORDER BY
+  created_at ASC;
+
+-- name: DeletePageLock :exec
+DELETE FROM page_lock
+WHERE page_id = @page_id
+  AND email = @email;
+
+-- name: DeleteStalePageLocks :execrows
+DELETE FROM page_lock
+WHERE heartbeat_at < @since::timestamptz;
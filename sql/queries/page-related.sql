@@ -0,0 +1,105 @@
+-- ListRelatedPageSuggestions ranks other published news pages
+-- by how well they match the title and description of the page with id,
+-- how many topics and series they share with it,
+-- and how close together they were published.
+-- name: ListRelatedPageSuggestions :many
+WITH source AS (
+  SELECT
+    page.id,
+    coalesce(page.publication_date, CURRENT_TIMESTAMP) AS publication_date,
+    ARRAY (
+      SELECT
+        jsonb_array_elements_text(
+          CASE WHEN jsonb_typeof(page.frontmatter -> 'topics') = 'array' THEN
+            page.frontmatter -> 'topics'
+          ELSE
+            '[]'::jsonb
+          END)) AS topics,
+    ARRAY (
+      SELECT
+        jsonb_array_elements_text(
+          CASE WHEN jsonb_typeof(page.frontmatter -> 'series') = 'array' THEN
+            page.frontmatter -> 'series'
+          ELSE
+            '[]'::jsonb
+          END)) AS series,
+    -- Any of the lexemes weighted A or B: titles and descriptions
+    array_to_string(ARRAY (
+        SELECT
+          quote_literal(lexeme)
+        FROM unnest(ts_filter(page.fts_doc_en, '{a,b}'))), ' | ')::tsquery AS tsq
+  FROM
+    page
+  WHERE
+    page.id = @id
+),
+scored AS (
+  SELECT
+    page.id,
+    (ts_rank(page.fts_doc_en, source.tsq, 32) * 4
+      + 0.5 * (
+        SELECT
+          count(*)
+        FROM unnest(source.topics) AS topic
+        WHERE
+          page.frontmatter -> 'topics' ? topic)
+      + (
+        SELECT
+          count(*)
+        FROM unnest(source.series) AS series
+        WHERE
+          page.frontmatter -> 'series' ? series)
+      + coalesce(exp(-abs(extract(epoch FROM page.publication_date -
+        source.publication_date)) / 86400 / 90), 0))::float8 AS score
+  FROM
+    page,
+    source
+  WHERE
+    page.id <> source.id
+    AND page.last_published IS NOT NULL
+    AND page.file_path ~ '^content/(news|statecollege|berks)/'
+    AND (page.fts_doc_en @@ source.tsq
+      OR page.frontmatter -> 'topics' ?| source.topics
+      OR page.frontmatter -> 'series' ?| source.series))
+SELECT
+  page.id,
+  page.file_path,
+  page.url_path,
+  coalesce(page.frontmatter ->> 'title', '')::text AS title,
+  page.publication_date,
+  scored.score
+FROM
+  scored
+  JOIN page USING (id)
+ORDER BY
+  scored.score DESC
+LIMIT @limit;
+
+-- name: ListRelatedPages :many
+SELECT
+  page.id,
+  page.file_path,
+  page.url_path,
+  coalesce(page.frontmatter ->> 'title', '')::text AS title,
+  page.publication_date,
+  page_related.score,
+  page_related.created_at
+FROM
+  page_related
+  JOIN page ON page.id = page_related.related_id
+WHERE
+  page_related.page_id = @page_id
+  AND page.last_published IS NOT NULL
+ORDER BY
+  page_related.score DESC;
+
+-- name: DeleteRelatedPages :exec
+DELETE FROM page_related
+WHERE page_id = @page_id;
+
+-- name: CreateRelatedPages :exec
+INSERT INTO page_related ("page_id", "related_id", "score")
+SELECT
+  @page_id::bigint,
+  unnest(@related_ids::bigint[]),
+  unnest(@scores::float8[]);
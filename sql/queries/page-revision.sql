@@ -0,0 +1,57 @@
+-- CreatePageRevision snapshots the current state of a page.
+-- It is a no-op if the page has not changed since its last revision.
+-- name: CreatePageRevision :exec
+INSERT INTO page_revision ("page_id", "frontmatter", "body", "schedule_for",
+  "last_published", "url_path", "created_by")
+SELECT
+  "id",
+  "frontmatter",
+  "body",
+  "schedule_for",
+  "last_published",
+  coalesce("url_path", ''),
+  @created_by::text
+FROM
+  page
+WHERE
+  page.id = @page_id
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM (
+      SELECT
+        pr.frontmatter,
+        pr.body,
+        pr.schedule_for,
+        pr.last_published
+      FROM
+        page_revision pr
+      WHERE
+        pr.page_id = page.id
+      ORDER BY
+        pr.id DESC
+      LIMIT 1) AS latest
+  WHERE
+    latest.frontmatter = page.frontmatter
+    AND latest.body = page.body
+    AND latest.schedule_for IS NOT DISTINCT FROM page.schedule_for
+    AND (latest.last_published IS NULL) = (page.last_published IS NULL));
+
+-- name: GetPageRevisionByID :one
+SELECT
+  *
+FROM
+  page_revision
+WHERE
+  id = $1;
+
+-- name: ListPageRevisions :many
+SELECT
+  *
+FROM
+  page_revision
+WHERE
+  page_id = $1
+ORDER BY
+  id DESC
+LIMIT $2 OFFSET $3;
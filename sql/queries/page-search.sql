@@ -0,0 +1,231 @@
+-- SearchPages lists the pages matching every filter that is set,
+-- newest first, starting after the cursor.
+-- Pages without a publication date sort by when they were created.
+-- name: SearchPages :many
+SELECT
+  id,
+  file_path::text,
+  coalesce(frontmatter ->> 'internal-id', '')::text AS "internal_id",
+  coalesce(frontmatter ->> 'title', '')::text AS "title",
+  coalesce(url_path, '')::text AS "url_path",
+  source_type::text,
+  (
+    CASE WHEN last_published IS NOT NULL THEN
+      'published'
+    WHEN schedule_for IS NOT NULL THEN
+      'scheduled'
+    ELSE
+      'draft'
+    END)::text AS "state",
+  last_published,
+  schedule_for,
+  publication_date,
+  coalesce(publication_date, created_at)::timestamptz AS "sort_date"
+FROM
+  page
+WHERE (@query::text = ''
+  OR fts_doc_en @@ websearch_to_tsquery('english', @query::text))
+AND (@section::text = ''
+  OR file_path LIKE @section::text || '%')
+AND (@author::text = ''
+  OR frontmatter @> jsonb_build_object('authors', jsonb_build_array(@author::text)))
+AND (@topic::text = ''
+  OR frontmatter @> jsonb_build_object('topics', jsonb_build_array(@topic::text)))
+AND (@series::text = ''
+  OR frontmatter @> jsonb_build_object('series', jsonb_build_array(@series::text)))
+AND (@state::text = ''
+  OR @state::text = (
+    CASE WHEN last_published IS NOT NULL THEN
+      'published'
+    WHEN schedule_for IS NOT NULL THEN
+      'scheduled'
+    ELSE
+      'draft'
+    END))
+AND (@source_type::text = ''
+  OR source_type = @source_type::text)
+AND (sqlc.narg(published_after)::timestamptz IS NULL
+  OR publication_date >= sqlc.narg(published_after)::timestamptz)
+AND (sqlc.narg(published_before)::timestamptz IS NULL
+  OR publication_date < sqlc.narg(published_before)::timestamptz)
+AND (sqlc.narg(cursor_date)::timestamptz IS NULL
+  OR (coalesce(publication_date, created_at), id) < (sqlc.narg(cursor_date)::timestamptz, @cursor_id::bigint))
+ORDER BY
+  coalesce(publication_date, created_at) DESC,
+  id DESC
+LIMIT @limit;
+
+-- SearchPageFacets counts the pages matching a search by section, author,
+-- topic, series, state, and source type.
+-- The counts for each facet ignore that facet's own filter,
+-- so they show how many pages choosing another value would match.
+-- Only the facet_limit most common values of each facet are returned.
+-- name: SearchPageFacets :many
+WITH base AS (
+  SELECT
+    file_path,
+    frontmatter,
+    source_type,
+    (
+      CASE WHEN last_published IS NOT NULL THEN
+        'published'
+      WHEN schedule_for IS NOT NULL THEN
+        'scheduled'
+      ELSE
+        'draft'
+      END)::text AS "state"
+  FROM
+    page
+  WHERE (@query::text = ''
+    OR fts_doc_en @@ websearch_to_tsquery('english', @query::text))
+  AND (sqlc.narg(published_after)::timestamptz IS NULL
+    OR publication_date >= sqlc.narg(published_after)::timestamptz)
+  AND (sqlc.narg(published_before)::timestamptz IS NULL
+    OR publication_date < sqlc.narg(published_before)::timestamptz)
+),
+matches AS (
+  SELECT
+    *,
+    (@section::text = ''
+      OR file_path LIKE @section::text || '%') AS m_section,
+    (@author::text = ''
+      OR frontmatter @> jsonb_build_object('authors', jsonb_build_array(@author::text))) AS m_author,
+    (@topic::text = ''
+      OR frontmatter @> jsonb_build_object('topics', jsonb_build_array(@topic::text))) AS m_topic,
+    (@series::text = ''
+      OR frontmatter @> jsonb_build_object('series', jsonb_build_array(@series::text))) AS m_series,
+    (@state::text = ''
+      OR "state" = @state::text) AS m_state,
+    (@source_type::text = ''
+      OR source_type = @source_type::text) AS m_source_type
+  FROM
+    base
+),
+counts AS (
+  SELECT
+    'section' AS facet,
+    'content/' || split_part(file_path, '/', 2) || '/' AS value,
+    count(*) AS count
+  FROM
+    matches
+  WHERE
+    m_author
+    AND m_topic
+    AND m_series
+    AND m_state
+    AND m_source_type
+  GROUP BY
+    value
+  UNION ALL
+  SELECT
+    'author',
+    author,
+    count(*)
+  FROM
+    matches,
+    jsonb_array_elements_text(
+      CASE WHEN jsonb_typeof(frontmatter -> 'authors') = 'array' THEN
+        frontmatter -> 'authors'
+      ELSE
+        '[]'::jsonb
+      END) AS author
+  WHERE
+    m_section
+    AND m_topic
+    AND m_series
+    AND m_state
+    AND m_source_type
+  GROUP BY
+    author
+  UNION ALL
+  SELECT
+    'topic',
+    topic,
+    count(*)
+  FROM
+    matches,
+    jsonb_array_elements_text(
+      CASE WHEN jsonb_typeof(frontmatter -> 'topics') = 'array' THEN
+        frontmatter -> 'topics'
+      ELSE
+        '[]'::jsonb
+      END) AS topic
+  WHERE
+    m_section
+    AND m_author
+    AND m_series
+    AND m_state
+    AND m_source_type
+  GROUP BY
+    topic
+  UNION ALL
+  SELECT
+    'series',
+    series,
+    count(*)
+  FROM
+    matches,
+    jsonb_array_elements_text(
+      CASE WHEN jsonb_typeof(frontmatter -> 'series') = 'array' THEN
+        frontmatter -> 'series'
+      ELSE
+        '[]'::jsonb
+      END) AS series
+  WHERE
+    m_section
+    AND m_author
+    AND m_topic
+    AND m_state
+    AND m_source_type
+  GROUP BY
+    series
+  UNION ALL
+  SELECT
+    'state',
+    "state",
+    count(*)
+  FROM
+    matches
+  WHERE
+    m_section
+    AND m_author
+    AND m_topic
+    AND m_series
+    AND m_source_type
+  GROUP BY
+    "state"
+  UNION ALL
+  SELECT
+    'source_type',
+    source_type,
+    count(*)
+  FROM
+    matches
+  WHERE
+    m_section
+    AND m_author
+    AND m_topic
+    AND m_series
+    AND m_state
+  GROUP BY
+    source_type
+),
+ranked AS (
+  SELECT
+    *,
+    row_number() OVER (PARTITION BY facet ORDER BY count DESC, value ASC) AS rank
+  FROM
+    counts
+)
+SELECT
+  facet::text,
+  value::text,
+  count::bigint
+FROM
+  ranked
+WHERE
+  rank <= @facet_limit::bigint
+ORDER BY
+  facet ASC,
+  count DESC,
+  value ASC;
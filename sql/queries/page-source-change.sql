@@ -0,0 +1,30 @@
+-- UpsertPageSourceChanges flags the pages made from a source as having changed
+-- as of changed_at, the time the new copy of the source was imported.
+-- name: UpsertPageSourceChanges :execrows
+INSERT INTO page_source_change ("page_id", "changed_at")
+SELECT
+  id,
+  @changed_at
+FROM
+  page
+WHERE
+  source_type = @source_type
+  AND source_id = @source_id
+ON CONFLICT ("page_id")
+  DO UPDATE SET
+    "changed_at" = excluded.changed_at;
+
+-- name: GetPageSourceChange :one
+SELECT
+  *
+FROM
+  page_source_change
+WHERE
+  page_id = $1;
+
+-- DeletePageSourceChange clears the flag on a page
+-- unless its source changed again after the copy imported at refreshed_at.
+-- name: DeletePageSourceChange :exec
+DELETE FROM page_source_change
+WHERE page_id = @page_id
+  AND changed_at <= @refreshed_at;
@@ -23,6 +23,11 @@ SET
   ELSE
     schedule_for
   END,
+  expire_at = CASE WHEN @set_expire_at::boolean THEN
+    @expire_at
+  ELSE
+    expire_at
+  END,
   url_path = CASE WHEN @url_path::text != '' THEN
     @url_path
   ELSE
@@ -35,6 +40,9 @@ SET
   END
 WHERE
   id = @id
+  -- Optimistic concurrency: skip the update if someone else saved first
+  AND (@expected_updated_at::timestamptz IS NULL
+    OR updated_at = @expected_updated_at::timestamptz)
 RETURNING
   *;
 
@@ -65,6 +73,19 @@ WHERE
 RETURNING
   *;
 
+-- name: PopExpiredPages :many
+UPDATE
+  page
+SET
+  last_published = NULL,
+  schedule_for = NULL,
+  expire_at = NULL
+WHERE
+  last_published IS NOT NULL
+  AND expire_at < CURRENT_TIMESTAMP
+RETURNING
+  *;
+
 -- name: ListPages :many
 WITH paths AS (
   SELECT
@@ -122,6 +143,25 @@ ORDER BY
   id ASC
 LIMIT $2 OFFSET $3;
 
+-- name: ListPageIDsByFilter :many
+SELECT
+  "id"
+FROM
+  page
+WHERE
+  "file_path" LIKE @file_path::text
+  AND (@topic::text = ''
+    OR frontmatter @> jsonb_build_object('topics', jsonb_build_array(@topic::text)))
+  AND (@author::text = ''
+    OR frontmatter @> jsonb_build_object('authors', jsonb_build_array(@author::text))
+    OR frontmatter ->> 'byline' ILIKE '%' || @author::text || '%')
+  AND (sqlc.narg(published_after)::timestamptz IS NULL
+    OR publication_date >= sqlc.narg(published_after)::timestamptz)
+  AND (sqlc.narg(published_before)::timestamptz IS NULL
+    OR publication_date < sqlc.narg(published_before)::timestamptz)
+ORDER BY
+  id ASC;
+
 -- name: GetPageByURLPath :one
 SELECT
   *
@@ -131,6 +171,19 @@ WHERE
   RTRIM(url_path, '/')
   ILIKE RTRIM(@url_path::text, '/');
 
+-- name: ListOtherPagesByURLPath :many
+SELECT
+  id,
+  file_path
+FROM
+  page
+WHERE
+  RTRIM(url_path, '/')
+  ILIKE RTRIM(@url_path::text, '/')
+  AND id <> @id
+ORDER BY
+  id ASC;
+
 -- name: ListAllTopics :many
 SELECT
   *
@@ -216,6 +269,29 @@ ORDER BY
   publication_date DESC
 LIMIT $1 OFFSET $2;
 
+-- ListPublishedPagesAfterID pages through published pages in ID order.
+-- name: ListPublishedPagesAfterID :many
+SELECT
+  *
+FROM
+  page
+WHERE
+  last_published IS NOT NULL
+  AND id > @after_id::bigint
+ORDER BY
+  id ASC
+LIMIT @limit;
+
+-- name: ListPagesByTaxonomyTerm :many
+SELECT
+  *
+FROM
+  page
+WHERE
+  frontmatter @> jsonb_build_object(@taxonomy::text, jsonb_build_array(@term::text))
+ORDER BY
+  id ASC;
+
 -- name: ListPagesByInternalID :many
 WITH query AS (
   SELECT
@@ -237,3 +313,14 @@ FROM
   JOIN query USING (id)
 ORDER BY
   publication_date DESC;
+
+-- name: UnpublishPage :one
+UPDATE
+  page
+SET
+  last_published = NULL,
+  schedule_for = NULL
+WHERE
+  id = $1
+RETURNING
+  *;
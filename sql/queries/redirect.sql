@@ -5,3 +5,18 @@ FROM
   "redirect"
 WHERE
   "from" = $1;
+
+-- name: UpsertRedirect :one
+INSERT INTO "redirect" ("from", "to", "code", "roles")
+  VALUES ($1, $2, $3, $4)
+ON CONFLICT ("from")
+  DO UPDATE SET
+    "to" = EXCLUDED."to",
+    "code" = EXCLUDED.code,
+    "roles" = EXCLUDED.roles
+  RETURNING
+    *;
+
+-- name: DeleteRedirect :exec
+DELETE FROM "redirect"
+WHERE "from" = $1;
@@ -64,3 +64,8 @@ ON CONFLICT ("key", "schedule_for")
 DELETE FROM site_data
 WHERE "key" = @key
   AND "schedule_for" > (CURRENT_TIMESTAMP + '5 minutes'::interval);
+
+-- LockSiteData serializes edits to a key until the end of the transaction.
+-- name: LockSiteData :exec
+SELECT
+  pg_advisory_xact_lock(hashtext(@key::text));
@@ -0,0 +1,36 @@
+-- ListSitemapPages returns every published page with a URL
+-- that search engines may index.
+-- name: ListSitemapPages :many
+SELECT
+  url_path::text,
+  publication_date,
+  last_published::timestamptz
+FROM
+  page
+WHERE
+  last_published IS NOT NULL
+  AND coalesce(url_path, '') <> ''
+  AND coalesce(frontmatter ->> 'no-index', '') <> 'true'
+ORDER BY
+  publication_date ASC NULLS FIRST,
+  url_path ASC;
+
+-- ListNewsSitemapPages returns news pages published since published_after,
+-- newest first.
+-- name: ListNewsSitemapPages :many
+SELECT
+  url_path::text,
+  publication_date::timestamptz,
+  coalesce(frontmatter ->> 'title', '')::text AS "title",
+  coalesce(nullif(frontmatter ->> 'language-code', ''), 'en')::text AS "language_code"
+FROM
+  page
+WHERE
+  last_published IS NOT NULL
+  AND coalesce(url_path, '') <> ''
+  AND coalesce(frontmatter ->> 'no-index', '') <> 'true'
+  AND file_path ~ '^content/(news|statecollege|berks)/'
+  AND publication_date > @published_after::timestamptz
+ORDER BY
+  publication_date DESC
+LIMIT @limit;
@@ -0,0 +1,33 @@
+CREATE TABLE page_revision (
+  "id" bigserial PRIMARY KEY,
+  "page_id" bigint NOT NULL REFERENCES page (id) ON DELETE CASCADE,
+  "frontmatter" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "body" text NOT NULL DEFAULT '',
+  "schedule_for" timestamptz,
+  "last_published" timestamptz,
+  "url_path" text NOT NULL DEFAULT '',
+  "created_by" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "page_revision_page_id_idx" ON "page_revision" ("page_id", "id" DESC);
+
+-- Start every existing page with its current state
+-- so the first edit after this migration can be undone
+INSERT INTO page_revision ("page_id", "frontmatter", "body", "schedule_for",
+  "last_published", "url_path", "created_at")
+SELECT
+  "id",
+  "frontmatter",
+  "body",
+  "schedule_for",
+  "last_published",
+  coalesce("url_path", ''),
+  "updated_at"
+FROM
+  page
+ORDER BY
+  "id";
+
+---- create above / drop below ----
+DROP TABLE page_revision;
@@ -0,0 +1,13 @@
+CREATE TABLE page_lock (
+  "page_id" bigint NOT NULL REFERENCES page (id) ON DELETE CASCADE,
+  "email" text NOT NULL,
+  "name" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "heartbeat_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("page_id", "email")
+);
+
+CREATE INDEX "page_lock_heartbeat_at_idx" ON "page_lock" ("heartbeat_at");
+
+---- create above / drop below ----
+DROP TABLE page_lock;
@@ -0,0 +1,12 @@
+ALTER TABLE "page"
+  ADD COLUMN "expire_at" timestamptz;
+
+CREATE INDEX "page_expire_at_idx" ON "page" ("expire_at")
+WHERE
+  "expire_at" IS NOT NULL;
+
+---- create above / drop below ----
+DROP INDEX "page_expire_at_idx";
+
+ALTER TABLE "page"
+  DROP COLUMN "expire_at";
@@ -0,0 +1,27 @@
+CREATE TABLE job (
+  "id" bigserial PRIMARY KEY,
+  "kind" text NOT NULL,
+  "params" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "result" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "total" integer NOT NULL DEFAULT 0,
+  "processed" integer NOT NULL DEFAULT 0,
+  "warnings" text[] NOT NULL DEFAULT '{}',
+  "error" text NOT NULL DEFAULT '',
+  "created_by" text NOT NULL DEFAULT '',
+  "started_at" timestamptz,
+  "finished_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "job_pending_idx" ON "job" ("id")
+WHERE
+  "started_at" IS NULL;
+
+CREATE TRIGGER row_updated_at_on_job_trigger_
+  BEFORE UPDATE ON "job"
+  FOR EACH ROW
+  EXECUTE PROCEDURE update_row_updated_at_function_ ();
+
+---- create above / drop below ----
+DROP TABLE job;
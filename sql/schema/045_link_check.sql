@@ -0,0 +1,28 @@
+CREATE TABLE link_check (
+  "id" bigserial PRIMARY KEY,
+  "page_id" bigint NOT NULL REFERENCES page (id) ON DELETE CASCADE,
+  "url" text NOT NULL,
+  "source" text NOT NULL DEFAULT '',
+  "internal" boolean NOT NULL DEFAULT FALSE,
+  "status" integer NOT NULL DEFAULT 0,
+  "error" text NOT NULL DEFAULT '',
+  "broken" boolean NOT NULL DEFAULT FALSE,
+  "checked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE ("page_id", "url")
+);
+
+CREATE INDEX "link_check_url_idx" ON "link_check" ("url");
+
+CREATE INDEX "link_check_broken_idx" ON "link_check" ("page_id")
+WHERE
+  "broken";
+
+CREATE TRIGGER row_updated_at_on_link_check_trigger_
+  BEFORE UPDATE ON "link_check"
+  FOR EACH ROW
+  EXECUTE PROCEDURE update_row_updated_at_function_ ();
+
+---- create above / drop below ----
+DROP TABLE link_check;
@@ -0,0 +1,10 @@
+CREATE TABLE page_related (
+  "page_id" bigint NOT NULL REFERENCES page (id) ON DELETE CASCADE,
+  "related_id" bigint NOT NULL REFERENCES page (id) ON DELETE CASCADE,
+  "score" double precision NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("page_id", "related_id")
+);
+
+---- create above / drop below ----
+DROP TABLE page_related;
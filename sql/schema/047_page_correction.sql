@@ -0,0 +1,22 @@
+CREATE TABLE page_correction (
+  "id" bigserial PRIMARY KEY,
+  "page_id" bigint NOT NULL REFERENCES page (id) ON DELETE CASCADE,
+  "kind" text NOT NULL CHECK ("kind" IN ('correction', 'clarification', 'update')),
+  "note" text NOT NULL,
+  "corrected_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "created_by" text NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "page_correction_page_id_idx" ON "page_correction" ("page_id");
+
+CREATE INDEX "page_correction_corrected_at_idx" ON "page_correction" ("corrected_at");
+
+CREATE TRIGGER row_updated_at_on_page_correction_trigger_
+  BEFORE UPDATE ON "page_correction"
+  FOR EACH ROW
+  EXECUTE PROCEDURE update_row_updated_at_function_ ();
+
+---- create above / drop below ----
+DROP TABLE page_correction;
@@ -0,0 +1,21 @@
+CREATE TABLE author (
+  "id" bigserial PRIMARY KEY,
+  "name" text NOT NULL,
+  "slug" text NOT NULL UNIQUE,
+  "title" text NOT NULL DEFAULT '',
+  "bio" text NOT NULL DEFAULT '',
+  "photo_id" bigint REFERENCES image (id) ON DELETE SET NULL,
+  "email" text NOT NULL DEFAULT '',
+  "social_links" jsonb NOT NULL DEFAULT '{}'::jsonb,
+  "active" boolean NOT NULL DEFAULT TRUE,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "updated_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER row_updated_at_on_author_trigger_
+  BEFORE UPDATE ON "author"
+  FOR EACH ROW
+  EXECUTE PROCEDURE update_row_updated_at_function_ ();
+
+---- create above / drop below ----
+DROP TABLE author;
@@ -0,0 +1,19 @@
+CREATE TABLE audit_event (
+  "id" bigserial PRIMARY KEY,
+  "email_address" text NOT NULL DEFAULT '',
+  "route" text NOT NULL,
+  "target" text NOT NULL DEFAULT '',
+  "status_code" int NOT NULL,
+  "before" jsonb NOT NULL DEFAULT 'null'::jsonb,
+  "after" jsonb NOT NULL DEFAULT 'null'::jsonb,
+  "created_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX "audit_event_created_at_idx" ON "audit_event" ("created_at");
+
+CREATE INDEX "audit_event_email_address_idx" ON "audit_event" ("email_address");
+
+CREATE INDEX "audit_event_target_idx" ON "audit_event" ("target");
+
+---- create above / drop below ----
+DROP TABLE audit_event;
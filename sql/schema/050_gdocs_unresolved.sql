@@ -0,0 +1,6 @@
+ALTER TABLE "g_docs_doc"
+  ADD COLUMN "unresolved" text[] NOT NULL DEFAULT '{}';
+
+---- create above / drop below ----
+ALTER TABLE "g_docs_doc"
+  DROP COLUMN "unresolved";
@@ -0,0 +1,7 @@
+CREATE TABLE page_source_change (
+  "page_id" bigint PRIMARY KEY REFERENCES page (id) ON DELETE CASCADE,
+  "changed_at" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+---- create above / drop below ----
+DROP TABLE page_source_change;
@@ -11,8 +11,12 @@ const responseError = async (rsp) => {
     return;
   }
   let details = {};
+  let current = null;
   try {
-    details = (await rsp.json())?.details ?? {};
+    let data = await rsp.json();
+    details = data?.details ?? {};
+    // Sent with 409 Conflict so the client can merge its changes
+    current = data?.current ?? null;
     // eslint-disable-next-line no-empty
   } catch (e) {}
 
@@ -20,6 +24,7 @@ const responseError = async (rsp) => {
   let err = new Error("Unexpected response from server: " + msg);
   err.name = msg;
   err.details = details;
+  err.current = current;
   return err;
 };
 
@@ -57,11 +62,11 @@ export function get(url, params) {
   return tryTo(request(url, { params }));
 }
 
-export function post(url, obj) {
+export function post(url, obj, headers = {}) {
   let body = JSON.stringify(obj);
   return tryTo(
     request(url, {
-      headers: { "Content-Type": "application/json" },
+      headers: { "Content-Type": "application/json", ...headers },
       options: {
         method: "POST",
         body,
@@ -74,10 +79,14 @@ export function post(url, obj) {
 // GET and POST listed as two endpoints
 export const listAllSeries = `/api/all-series`;
 export const listAllTopics = `/api/all-topics`;
+export const listAuditLog = `/api/audit-log`;
+export const postAuthor = `/api/author`;
 export const postAuthorizedDomain = `/api/authorized-domains`;
 export const listAuthorizedDomains = `/api/authorized-domains`;
 export const postAuthorizedEmailAddress = `/api/authorized-addresses`;
 export const listAuthorizedEmailAddresses = `/api/authorized-addresses`;
+export const listAuthors = `/api/authors`;
+export const getCalendar = `/api/calendar`;
 export const createSignedUpload = `/api/create-signed-upload`;
 export const postDonorWall = `/api/donor-wall`;
 export const createFile = `/api/files-create`;
@@ -87,15 +96,27 @@ export const getGDocsDoc = `/api/gdocs-doc`;
 export const postGDocsDoc = `/api/gdocs-doc`;
 export const postImageUpdate = `/api/image-update`;
 export const listImages = `/api/images`;
+export const getJob = `/api/job`;
+export const getLinkCheckReport = `/api/link-check-report`;
 export const sendMessage = `/api/message`;
 export const getPage = `/api/page`;
 export const postPage = `/api/page`;
+export const postPageBulkEdit = `/api/page-bulk-edit`;
 export const postPageJSON = `/api/page-json`;
+export const postPageCorrection = `/api/page-correction`;
+export const listPageCorrections = `/api/page-corrections`;
 export const postPageCreate = `/api/page-create`;
 export const postPageLoad = `/api/page-load`;
+export const postPageLock = `/api/page-lock`;
+export const getPageRelated = `/api/page-related`;
 export const postPageRefresh = `/api/page-refresh`;
+export const postPageRestore = `/api/page-restore`;
+export const getPageRevisionDiff = `/api/page-revision-diff`;
+export const listPageRevisions = `/api/page-revisions`;
+export const postPageUnpublish = `/api/page-unpublish`;
 export const listPages = `/api/pages`;
 export const listPagesByFTS = `/api/pages-by-fts`;
+export const searchPages = `/api/pages-search`;
 export const getSharedArticle = `/api/shared-article`;
 export const postSharedArticle = `/api/shared-article`;
 export const postSharedArticleFromGDocs = `/api/shared-article-from-gdocs`;
@@ -106,6 +127,8 @@ export const getSiteData = `/api/site-data`;
 export const postSiteData = `/api/site-data`;
 export const getSiteParams = `/api/site-params`;
 export const postSiteParams = `/api/site-params`;
+export const postTaxonomyMerge = `/api/taxonomy-merge`;
+export const postTaxonomyRename = `/api/taxonomy-rename`;
 
 export async function uploadImage(body) {
   let [data, err] = await post(createSignedUpload, { type: body.type });
@@ -63,9 +63,12 @@ export default function usePicks({ fetchData, saveData }) {
     },
     save() {
       return edPickExec(() =>
-        saveData({
-          configs: state.allEdPicks,
-        })
+        saveData(
+          {
+            configs: state.allEdPicks,
+          },
+          edPicksState.rawData.value?.etag
+        )
       );
     },
     reset() {
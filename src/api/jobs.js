@@ -0,0 +1,31 @@
+import { getJob, get, post } from "./client-v2";
+import { wait } from "@/utils/wait.ts";
+
+export async function runJob(url, params, onProgress = () => {}) {
+  // Create job
+  let [job, err] = await post(url, params);
+  if (err) {
+    return [null, err];
+  }
+  onProgress(job);
+  // Kick off task runner
+  try {
+    await window.fetch("/api-background/jobs");
+  } catch (err) {
+    return [null, err];
+  }
+
+  // Poll while waiting for job to complete
+  while (!job.finished_at) {
+    await wait(1000);
+    [job, err] = await get(getJob, { id: job.id });
+    if (err) {
+      return [null, err];
+    }
+    onProgress(job);
+  }
+  if (job.error) {
+    return [job, new Error(job.error)];
+  }
+  return [job, null];
+}
@@ -1,4 +1,11 @@
-import { computed, reactive, ref, toRefs, watch } from "vue";
+import {
+  computed,
+  onUnmounted,
+  reactive,
+  ref,
+  toRefs,
+  watch,
+} from "vue";
 
 import { makeState, watchAPI } from "@/api/service-util.js";
 import {
@@ -9,6 +16,7 @@ import {
   listAllSeries,
   listImages,
   postPage,
+  postPageLock,
   postPageRefresh,
 } from "@/api/client-v2.js";
 import { processGDocsDoc } from "@/api/gdocs.js";
@@ -28,8 +36,13 @@ export class Page {
     this.createdAt = data["created_at"] ?? "";
     this.publicationDate = maybeDate(this.frontmatter, "published");
     this.updatedAt = maybeDate(data, "updated_at");
+    this.rawUpdatedAt = data["updated_at"] ?? null;
     this.lastPublished = maybeDate(data, "last_published");
     this.scheduleFor = maybeDate(data, "schedule_for");
+    this.expireAt = maybeDate(data, "expire_at");
+    // Set when the content was just refreshed from its source
+    this.sourceRefreshedAt = data["source_refreshed_at"] ?? null;
+    this.warnings = data["warnings"] ?? [];
     this.eventDate = maybeDate(this.frontmatter, "event-date");
     this.eventTitle = this.frontmatter["event-title"] ?? "";
     this.eventURL = this.frontmatter["event-url"] ?? "";
@@ -68,6 +81,7 @@ export class Page {
     this.noIndex = this.frontmatter["no-index"] ?? null;
     this.overrideURL = this.frontmatter["url"] ?? "";
     this.aliases = this.frontmatter["aliases"] ?? [];
+    this.related = this.frontmatter["related"] ?? [];
     this.layout = this.frontmatter["layout"] ?? "";
     this.feedExclude = this.frontmatter["feed-exclude"] ?? false;
     this.contentSource = this.frontmatter["content-source"] ?? "";
@@ -251,6 +265,7 @@ export class Page {
         "no-index": this.noIndex,
         url: toRel(this.overrideURL),
         aliases: this.aliases.map(toRel),
+        related: this.related,
         layout: this.layout,
         "feed-exclude": this.feedExclude,
         "content-source": this.contentSource,
@@ -260,9 +275,15 @@ export class Page {
       body: this.body,
       set_schedule_for: true,
       schedule_for: this.scheduleFor,
+      set_expire_at: true,
+      expire_at: this.expireAt,
       // leave blank to prevent changes by default
       url_path: this.shouldUpdateURLPath ? this.urlPath : "",
       set_last_published: false,
+      // reject the save if someone else has saved since this copy was loaded
+      expected_updated_at: this.rawUpdatedAt,
+      // clears the source change warning if nothing changed since the refresh
+      source_refreshed_at: this.sourceRefreshedAt,
     };
   }
 }
@@ -292,7 +313,12 @@ export function usePage(id) {
 
   const fetch = (id) =>
     exec(() =>
-      clientGet(getPage, { by: "id", value: id, refresh_content_store: true })
+      clientGet(getPage, {
+        by: "id",
+        value: id,
+        refresh_content_store: true,
+        lock: true,
+      })
     );
   const post = (page) => exec(() => clientPost(postPage, page));
 
@@ -304,6 +330,44 @@ export function usePage(id) {
     immediate: true,
   });
 
+  // Let other editors know this page is open
+  const editors = ref([]);
+  watch(
+    () => apiState.rawData,
+    (data) => {
+      editors.value = data?.editors ?? [];
+    }
+  );
+  const heartbeat = async () => {
+    let [data, err] = await clientPost(postPageLock, { id: "" + id.value });
+    if (!err) {
+      editors.value = data.editors;
+    }
+  };
+  const heartbeatID = window.setInterval(heartbeat, 60 * 1000);
+  onUnmounted(() => {
+    window.clearInterval(heartbeatID);
+    clientPost(postPageLock, { id: "" + id.value, release: true });
+  });
+
+  // Set when the Google Doc was edited after the last refresh
+  const sourceChangedAt = computed(() =>
+    apiState.rawData?.source_changed_at
+      ? new Date(apiState.rawData.source_changed_at)
+      : null
+  );
+
+  // Messages for individual fields from the server's frontmatter validation
+  const fieldErrors = computed(() => {
+    let errors = {};
+    for (let [key, msgs] of Object.entries(apiState.error?.details ?? {})) {
+      if (key) {
+        errors[key] = msgs.join(" ");
+      }
+    }
+    return errors;
+  });
+
   const { apiState: imageState, exec: execImage } = makeState();
   execImage(() => clientGet(listImages));
 
@@ -316,6 +380,9 @@ export function usePage(id) {
     fetch,
     post,
     page,
+    editors,
+    sourceChangedAt,
+    fieldErrors,
 
     deriveSlug() {
       page.value.slug = page.value.title
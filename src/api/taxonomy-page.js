@@ -9,6 +9,7 @@ export class TaxonomyPage {
     this.frontmatter = data["frontmatter"] ?? {};
     this.filePath = data["file_path"] ?? "";
     this.urlPath = data["url_path"] ?? "";
+    this.rawUpdatedAt = data["updated_at"] ?? null;
     this.lastPublished = maybeDate(data, "last_published");
     this.publicationDate = maybeDate(this.frontmatter, "published");
     this.kicker = this.frontmatter["kicker"] ?? "";
@@ -171,6 +172,8 @@ export class TaxonomyPage {
       set_schedule_for: false,
       url_path: this.shouldUpdateURLPath ? this.urlPath : "",
       set_last_published: false,
+      // reject the save if someone else has saved since this copy was loaded
+      expected_updated_at: this.rawUpdatedAt,
     };
   }
 }
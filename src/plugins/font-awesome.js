@@ -9,6 +9,7 @@ import {
 import {
   faArrowDown,
   faArrowUp,
+  faCalendarDays,
   faCheckCircle,
   faCircleExclamation,
   faFileDownload,
@@ -16,7 +17,9 @@ import {
   faFileInvoice,
   faFileSignature,
   faFileUpload,
+  faHourglassEnd,
   faLink,
+  faMagnifyingGlass,
   faMailBulk,
   faNewspaper,
   faPaperPlane,
@@ -36,6 +39,7 @@ import {
 library.add(
   faArrowDown,
   faArrowUp,
+  faCalendarDays,
   faCheckCircle,
   faCircleExclamation,
   faCopy,
@@ -46,7 +50,9 @@ library.add(
   faFileSignature,
   faFileUpload,
   faFileWord,
+  faHourglassEnd,
   faLink,
+  faMagnifyingGlass,
   faMailBulk,
   faNewspaper,
   faPaperPlane,
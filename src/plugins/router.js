@@ -232,16 +232,38 @@ let router = createRouter({
       path: "/admin/topics",
       name: "topic-pages",
       component: load(() => import("@/components/ViewTaxonomyList.vue")),
-      props: { title: "Topics", apiPath: listAllTopics },
+      props: { title: "Topics", apiPath: listAllTopics, taxonomy: "topics" },
       meta: {
         requiresAuth: isSpotlightPAUser,
       },
     },
+    {
+      path: "/admin/audit-log",
+      name: "audit-log",
+      component: load(() => import("@/components/ViewAuditLog.vue")),
+      meta: { requiresAuth: isSpotlightPAUser },
+    },
+    {
+      path: "/admin/authors",
+      name: "authors",
+      component: load(() => import("@/components/ViewAuthors.vue")),
+      meta: { requiresAuth: isSpotlightPAUser },
+    },
+    {
+      path: "/admin/calendar",
+      name: "calendar",
+      component: load(() => import("@/components/ViewCalendar.vue")),
+      meta: { requiresAuth: isSpotlightPAUser },
+    },
     {
       path: "/admin/series",
       name: "series-pages",
       component: load(() => import("@/components/ViewTaxonomyList.vue")),
-      props: { title: "Investigation Series", apiPath: listAllSeries },
+      props: {
+        title: "Investigation Series",
+        apiPath: listAllSeries,
+        taxonomy: "series",
+      },
       meta: {
         requiresAuth: isSpotlightPAUser,
       },
@@ -301,12 +323,30 @@ let router = createRouter({
         requiresAuth: isSpotlightPAUser,
       },
     },
+    {
+      path: "/admin/link-check",
+      name: "link-check",
+      component: load(() => import("@/components/ViewLinkCheck.vue")),
+      meta: { requiresAuth: isSpotlightPAUser },
+    },
+    {
+      path: "/admin/page-bulk-edit",
+      name: "page-bulk-edit",
+      component: load(() => import("@/components/ViewPageBulkEdit.vue")),
+      meta: { requiresAuth: isSpotlightPAUser },
+    },
     {
       path: "/admin/page-load",
       name: "page-load",
       component: load(() => import("@/components/ViewPageLoad.vue")),
       meta: { requiresAuth: isSpotlightPAUser },
     },
+    {
+      path: "/admin/page-search",
+      name: "page-search",
+      component: load(() => import("@/components/ViewPageSearch.vue")),
+      meta: { requiresAuth: isSpotlightPAUser },
+    },
     {
       path: "/:pathMatch(.*)*",
       name: "error",